@@ -0,0 +1,255 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/svanharmelen/jsonapi"
+)
+
+// maxBatchSize is the largest number of creates sent in one bulk request.
+const maxBatchSize = 50
+
+// Create sends a create request for opts to path and decodes the created
+// object into a new R. O and R must be pointers to jsonapi structs, such as
+// *ServerCreateOptions and *Server.
+//
+// If the client was configured WithBatchWindow, concurrent creates to the
+// same path within the window are coalesced into a single JSON:API bulk
+// request, whose results are fanned back out to each caller. The API
+// rejects a whole bulk request if any object in it is invalid, so on a 422
+// each create is retried on its own and only the invalid ones fail.
+//
+// Cancelling ctx abandons a create that is still waiting for its batch. Once
+// the batch has been sent, Create waits for its result instead, so an object
+// the API creates is never lost.
+func Create[O, R any](ctx context.Context, c *Client, path string, opts O) (R, error) {
+	if c.batchWindow <= 0 {
+		return createOne[O, R](ctx, c, path, opts)
+	}
+
+	// Creates of different types to the same path are batched apart.
+	key := batcherKey{path: path, opts: reflect.TypeFor[O](), result: reflect.TypeFor[R]()}
+	b, _ := c.batchers.LoadOrStore(key, &batcher[O, R]{client: c, path: path})
+	return b.(*batcher[O, R]).create(ctx, opts)
+}
+
+// batcherKey identifies a client's batcher for one path and type of create.
+type batcherKey struct {
+	path         string
+	opts, result reflect.Type
+}
+
+// createOne sends a single, unbatched create request.
+func createOne[O, R any](ctx context.Context, c *Client, path string, opts O) (R, error) {
+	var result R
+
+	req, err := c.NewRequest("POST", path, opts)
+	if err != nil {
+		return result, err
+	}
+
+	result = newModel[R]()
+	if err := c.Do(ctx, req, result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// newModel returns a pointer to a new zero value for the pointer type R.
+func newModel[R any]() R {
+	var zero R
+	return reflect.New(reflect.TypeOf(zero).Elem()).Interface().(R)
+}
+
+// batcher collects creates for one path until its window closes or it is
+// full, then sends them together.
+type batcher[O, R any] struct {
+	client *Client
+	path   string
+
+	mu      sync.Mutex
+	pending []*batchItem[O, R]
+	timer   *time.Timer
+
+	// batch counts the batches started, so a timer that fires after its
+	// batch was sent or abandoned cannot flush the next one early.
+	batch int
+}
+
+type batchItem[O, R any] struct {
+	ctx  context.Context
+	opts O
+	done chan batchResult[R]
+}
+
+type batchResult[R any] struct {
+	result R
+	err    error
+}
+
+func (b *batcher[O, R]) create(ctx context.Context, opts O) (R, error) {
+	item := &batchItem[O, R]{
+		ctx:  ctx,
+		opts: opts,
+		done: make(chan batchResult[R], 1),
+	}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, item)
+	switch {
+	case len(b.pending) >= maxBatchSize:
+		b.flushLocked()
+	case len(b.pending) == 1:
+		b.batch++
+		batch := b.batch
+		b.timer = time.AfterFunc(b.client.batchWindow, func() { b.flush(batch) })
+	}
+	b.mu.Unlock()
+
+	select {
+	case res := <-item.done:
+		return res.result, res.err
+	case <-ctx.Done():
+	}
+
+	if b.remove(item) {
+		var zero R
+		return zero, ctx.Err()
+	}
+
+	// The item is already being sent.
+	res := <-item.done
+	return res.result, res.err
+}
+
+// remove takes item out of the pending batch, reporting whether it was
+// still there.
+func (b *batcher[O, R]) remove(item *batchItem[O, R]) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, pending := range b.pending {
+		if pending != item {
+			continue
+		}
+
+		b.pending = append(b.pending[:i], b.pending[i+1:]...)
+		if len(b.pending) == 0 && b.timer != nil {
+			b.timer.Stop()
+			b.timer = nil
+		}
+		return true
+	}
+
+	return false
+}
+
+// flush sends the pending creates if they are still the given batch.
+func (b *batcher[O, R]) flush(batch int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if batch != b.batch {
+		return
+	}
+	b.flushLocked()
+}
+
+// flushLocked sends all pending creates. b.mu must be held.
+func (b *batcher[O, R]) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	items := b.pending
+	b.pending = nil
+	if len(items) == 0 {
+		return
+	}
+
+	go b.send(items)
+}
+
+// send creates the given items, as a bulk request if there is more than
+// one, and delivers each caller its result.
+func (b *batcher[O, R]) send(items []*batchItem[O, R]) {
+	// The batch outlives any one caller, so it keeps the first caller's
+	// logger and trace but not its cancellation.
+	ctx := context.WithoutCancel(items[0].ctx)
+
+	if len(items) == 1 {
+		result, err := createOne[O, R](ctx, b.client, b.path, items[0].opts)
+		items[0].done <- batchResult[R]{result, err}
+		return
+	}
+
+	results, err := b.sendBulk(ctx, items)
+	if hasStatus(err, http.StatusUnprocessableEntity) {
+		b.sendEach(items)
+		return
+	}
+	for i, item := range items {
+		if err != nil {
+			item.done <- batchResult[R]{err: err}
+			continue
+		}
+		item.done <- batchResult[R]{result: results[i]}
+	}
+}
+
+// sendEach creates the given items with one request each, concurrently.
+func (b *batcher[O, R]) sendEach(items []*batchItem[O, R]) {
+	for _, item := range items {
+		go func() {
+			ctx := context.WithoutCancel(item.ctx)
+			result, err := createOne[O, R](ctx, b.client, b.path, item.opts)
+			item.done <- batchResult[R]{result, err}
+		}()
+	}
+}
+
+// sendBulk sends a JSON:API bulk create and returns the created objects in
+// the same order as items.
+func (b *batcher[O, R]) sendBulk(ctx context.Context, items []*batchItem[O, R]) ([]R, error) {
+	opts := make([]O, 0, len(items))
+	for _, item := range items {
+		opts = append(opts, item.opts)
+	}
+
+	req, err := b.client.NewRequest("POST", b.path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	body := bytes.NewBuffer(nil)
+	if err := b.client.Do(ctx, req, body); err != nil {
+		return nil, err
+	}
+
+	var zero R
+	raw, err := jsonapi.UnmarshalManyPayload(body, reflect.TypeOf(zero))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != len(items) {
+		return nil, fmt.Errorf("bulk create of %d %s returned %d results", len(items), b.path, len(raw))
+	}
+
+	results := make([]R, 0, len(raw))
+	for _, r := range raw {
+		results = append(results, r.(R))
+	}
+
+	return results, nil
+}
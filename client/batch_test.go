@@ -0,0 +1,266 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// bulkServer creates test objects, singly or in bulk, rejecting a request
+// that holds an object named "invalid" as the API does.
+type bulkServer struct {
+	requests atomic.Int32
+	created  atomic.Int32
+
+	// release, if set, holds each request until it is closed.
+	release chan struct{}
+}
+
+func (s *bulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+
+	var doc struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	type resource struct {
+		Type       string            `json:"type"`
+		ID         string            `json:"id,omitempty"`
+		Attributes map[string]string `json:"attributes"`
+	}
+	var resources []resource
+	bulk := strings.HasPrefix(strings.TrimSpace(string(doc.Data)), "[")
+	if bulk {
+		json.Unmarshal(doc.Data, &resources)
+	} else {
+		resources = make([]resource, 1)
+		json.Unmarshal(doc.Data, &resources[0])
+	}
+
+	for _, res := range resources {
+		if res.Attributes["name"] == "invalid" {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"errors": [{"status": "422", "title": "Invalid test_objects", "detail": "name is invalid"}]}`))
+			return
+		}
+	}
+
+	for i := range resources {
+		resources[i].ID = fmt.Sprintf("obj-%d", s.created.Add(1))
+	}
+
+	var out interface{} = resources
+	if !bulk {
+		out = resources[0]
+	}
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": out})
+}
+
+// createConcurrently creates an object for each name at once, returning
+// the results in the same order.
+func createConcurrently(c *Client, names ...string) ([]*testObject, []error) {
+	objs := make([]*testObject, len(names))
+	errs := make([]error, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			objs[i], errs[i] = Create[*testObjectCreateOptions, *testObject](
+				context.Background(), c, "test_objects", &testObjectCreateOptions{Name: String(name)})
+		}()
+	}
+	wg.Wait()
+
+	return objs, errs
+}
+
+func TestCreate_batched(t *testing.T) {
+	srv := &bulkServer{}
+	c := newTestClient(t, newTestServer(t, srv), WithBatchWindow(100*time.Millisecond))
+
+	names := []string{"a", "b", "c"}
+	objs, errs := createConcurrently(c, names...)
+
+	for i, name := range names {
+		if errs[i] != nil {
+			t.Fatalf("error creating %s: %v", name, errs[i])
+		}
+		if objs[i].Name != name || objs[i].ID == "" {
+			t.Errorf("got %+v for %s", objs[i], name)
+		}
+	}
+	if got := srv.requests.Load(); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+}
+
+func TestCreate_batchedInvalidItem(t *testing.T) {
+	srv := &bulkServer{}
+	c := newTestClient(t, newTestServer(t, srv), WithBatchWindow(100*time.Millisecond))
+
+	objs, errs := createConcurrently(c, "a", "invalid", "b")
+
+	if errs[0] != nil || errs[2] != nil {
+		t.Fatalf("valid creates failed: %v, %v", errs[0], errs[2])
+	}
+	if objs[0].Name != "a" || objs[2].Name != "b" {
+		t.Errorf("got %+v and %+v", objs[0], objs[2])
+	}
+	if errs[1] == nil || !strings.Contains(errs[1].Error(), "name is invalid") {
+		t.Errorf("got error %v for the invalid create", errs[1])
+	}
+
+	// The bulk request, then one for each create.
+	if got := srv.requests.Load(); got != 4 {
+		t.Errorf("got %d requests, want 4", got)
+	}
+}
+
+func TestCreate_cancelledBeforeSend(t *testing.T) {
+	srv := &bulkServer{}
+	window := 200 * time.Millisecond
+	c := newTestClient(t, newTestServer(t, srv), WithBatchWindow(window))
+
+	ctx, cancel := context.WithTimeout(context.Background(), window/4)
+	defer cancel()
+
+	_, err := Create[*testObjectCreateOptions, *testObject](ctx, c, "test_objects", &testObjectCreateOptions{Name: String("a")})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The abandoned create is never sent.
+	time.Sleep(2 * window)
+	if got := srv.requests.Load(); got != 0 {
+		t.Errorf("got %d requests, want 0", got)
+	}
+}
+
+func TestCreate_cancelledAfterSend(t *testing.T) {
+	srv := &bulkServer{release: make(chan struct{})}
+	c := newTestClient(t, newTestServer(t, srv), WithBatchWindow(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// Cancel once the create reaches the API, then let it finish.
+		for srv.requests.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+		close(srv.release)
+	}()
+
+	obj, err := Create[*testObjectCreateOptions, *testObject](ctx, c, "test_objects", &testObjectCreateOptions{Name: String("a")})
+	if err != nil {
+		t.Fatalf("got error %v, want the created object", err)
+	}
+	if obj.ID == "" || obj.Name != "a" {
+		t.Errorf("got %+v", obj)
+	}
+}
+
+// otherObject is a second model served at the same path as testObject.
+type otherObject struct {
+	ID   string `jsonapi:"primary,test-objects"`
+	Name string `jsonapi:"attr,name,omitempty"`
+}
+
+type otherObjectCreateOptions struct {
+	ID   string  `jsonapi:"primary,test-objects"`
+	Name *string `jsonapi:"attr,name"`
+}
+
+func TestCreate_batchedDifferentTypes(t *testing.T) {
+	srv := &bulkServer{}
+	c := newTestClient(t, newTestServer(t, srv), WithBatchWindow(50*time.Millisecond))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if _, err := Create[*testObjectCreateOptions, *testObject](
+			context.Background(), c, "test_objects", &testObjectCreateOptions{Name: String("a")}); err != nil {
+			t.Error(err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		obj, err := Create[*otherObjectCreateOptions, *otherObject](
+			context.Background(), c, "test_objects", &otherObjectCreateOptions{Name: String("b")})
+		if err != nil {
+			t.Error(err)
+		} else if obj.Name != "b" {
+			t.Errorf("got %+v", obj)
+		}
+	}()
+	wg.Wait()
+
+	// Each type gets a batch of its own.
+	if got := srv.requests.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2", got)
+	}
+}
+
+func TestBatcher_staleTimer(t *testing.T) {
+	srv := &bulkServer{}
+	c := newTestClient(t, newTestServer(t, srv), WithBatchWindow(time.Hour))
+	b := &batcher[*testObjectCreateOptions, *testObject]{client: c, path: "test_objects"}
+
+	// The first batch is sent for being full, before its timer fires.
+	b.mu.Lock()
+	b.batch = 1
+	b.flushLocked()
+	b.mu.Unlock()
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := b.create(context.Background(), &testObjectCreateOptions{Name: String("a")})
+		result <- err
+	}()
+	for {
+		b.mu.Lock()
+		started := len(b.pending) == 1
+		b.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The first batch's timer leaves the second batch waiting.
+	b.flush(1)
+	b.mu.Lock()
+	pending := len(b.pending)
+	b.mu.Unlock()
+	if pending != 1 {
+		t.Fatalf("a stale timer flushed the next batch")
+	}
+
+	b.flush(2)
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// CassetteEnv names a cassette file to record API interactions to or
+	// replay them from. Files ending in .yaml or .yml are YAML; anything
+	// else is JSON.
+	CassetteEnv = "FWS_CASSETTE"
+
+	// CassetteModeEnv is "record" or "replay" (the default) and says what
+	// to do with the cassette named by CassetteEnv.
+	CassetteModeEnv = "FWS_CASSETTE_MODE"
+)
+
+// CassetteMode says whether a cassette records or replays interactions.
+type CassetteMode string
+
+const (
+	// CassetteRecord sends requests to the API and appends each
+	// interaction to the cassette. Delete the cassette first to record it
+	// from scratch.
+	CassetteRecord CassetteMode = "record"
+
+	// CassetteReplay answers requests from the cassette without touching
+	// the network.
+	CassetteReplay CassetteMode = "replay"
+)
+
+// WithCassette records API interactions to, or replays them from, the
+// cassette at path. Without this option, NewClient uses the cassette named
+// by FWS_CASSETTE, if any.
+func WithCassette(path string, mode CassetteMode) Option {
+	return func(o *options) {
+		o.cassettePath = path
+		o.cassetteMode = mode
+	}
+}
+
+// cassetteFromEnv returns the cassette path and mode from the environment.
+func cassetteFromEnv() (string, CassetteMode) {
+	mode := CassetteMode(os.Getenv(CassetteModeEnv))
+	if mode == "" {
+		mode = CassetteReplay
+	}
+	return os.Getenv(CassetteEnv), mode
+}
+
+// Interaction is a recorded request and the API's response to it.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request" yaml:"request"`
+	Response RecordedResponse `json:"response" yaml:"response"`
+}
+
+// RecordedRequest is the part of a request replays are matched on. Secret
+// attributes in the body are redacted, and headers are not recorded, so
+// cassettes never hold credentials.
+type RecordedRequest struct {
+	Method string `json:"method" yaml:"method"`
+	Path   string `json:"path" yaml:"path"`
+	Body   string `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// RecordedResponse is a response as replayed from a cassette.
+type RecordedResponse struct {
+	Status  int               `json:"status" yaml:"status"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body    string            `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// recordedHeaders are the response headers kept in cassettes.
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+// cassette holds the interactions of one cassette file. It is shared by
+// every client in the process using the same file.
+type cassette struct {
+	path string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = map[string]*cassette{}
+)
+
+// openCassette loads the cassette at path. A missing file is an empty
+// cassette when recording, and an error when replaying.
+func openCassette(path string, mode CassetteMode) (*cassette, error) {
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+
+	if c, ok := cassettes[path]; ok {
+		return c, nil
+	}
+
+	c := &cassette{path: path}
+
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && mode == CassetteRecord:
+	case err != nil:
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	default:
+		if err := c.unmarshal(raw); err != nil {
+			return nil, fmt.Errorf("error decoding cassette %s: %w", path, err)
+		}
+	}
+
+	c.used = make([]bool, len(c.interactions))
+	cassettes[path] = c
+
+	return c, nil
+}
+
+func (c *cassette) isYAML() bool {
+	ext := strings.ToLower(filepath.Ext(c.path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func (c *cassette) unmarshal(raw []byte) error {
+	if c.isYAML() {
+		return yaml.Unmarshal(raw, &c.interactions)
+	}
+	return json.Unmarshal(raw, &c.interactions)
+}
+
+// record appends an interaction and rewrites the cassette file, so nothing
+// is lost if the process is killed.
+func (c *cassette) record(i Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, i)
+	c.used = append(c.used, true)
+
+	var raw []byte
+	var err error
+	if c.isYAML() {
+		raw, err = yaml.Marshal(c.interactions)
+	} else {
+		raw, err = json.MarshalIndent(c.interactions, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, raw, 0o644)
+}
+
+// match returns the response to req: the first unused matching interaction
+// in the order they were recorded, or the last matching one if all have
+// been used, since a provider run may repeat reads the recording did not.
+func (c *cassette) match(req RecordedRequest) (RecordedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := -1
+	for i, in := range c.interactions {
+		if in.Request != req {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return in.Response, true
+		}
+		last = i
+	}
+
+	if last < 0 {
+		return RecordedResponse{}, false
+	}
+	return c.interactions[last].Response, true
+}
+
+// cassetteMissError is returned when replaying a request that is not in the
+// cassette. Retrying it cannot help.
+type cassetteMissError struct {
+	path string
+	req  RecordedRequest
+}
+
+func (e *cassetteMissError) Error() string {
+	return fmt.Sprintf("cassette %s has no interaction for %s %s", e.path, e.req.Method, e.req.Path)
+}
+
+// cassetteRetryPolicy is retryablehttp's default policy, except that
+// cassette misses are not retried.
+func cassetteRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	var miss *cassetteMissError
+	if errors.As(err, &miss) {
+		return false, nil
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// cassetteTransport records interactions sent through next to a cassette,
+// or replays them from it when next is nil.
+type cassetteTransport struct {
+	next     http.RoundTripper
+	cassette *cassette
+}
+
+// newCassetteTransport wraps next in a transport recording to, or replaying
+// from, the cassette at path.
+func newCassetteTransport(next http.RoundTripper, path string, mode CassetteMode) (http.RoundTripper, error) {
+	switch mode {
+	case CassetteRecord:
+	case CassetteReplay:
+		next = nil
+	default:
+		return nil, fmt.Errorf("invalid %s %q: must be %q or %q", CassetteModeEnv, mode, CassetteRecord, CassetteReplay)
+	}
+
+	c, err := openCassette(path, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cassetteTransport{next: next, cassette: c}, nil
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.next == nil {
+		res, ok := t.cassette.match(recorded)
+		if !ok {
+			return nil, &cassetteMissError{path: t.cassette.path, req: recorded}
+		}
+		return replayResponse(req, res), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	headers := map[string]string{}
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			headers[h] = v
+		}
+	}
+
+	err = t.cassette.record(Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    cassetteBody(body),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error recording cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+// recordRequest returns the recorded form of req, leaving its body intact.
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	var body []byte
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return RecordedRequest{}, err
+		}
+		body, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return RecordedRequest{}, err
+		}
+	}
+
+	return RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.RequestURI(),
+		Body:   cassetteBody(body),
+	}, nil
+}
+
+// cassetteBody returns a body as stored in a cassette: JSON is redacted
+// and re-encoded with sorted keys, so equal documents always match.
+func cassetteBody(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	if !json.Valid(raw) {
+		return string(raw)
+	}
+	return redactBody(raw)
+}
+
+func replayResponse(req *http.Request, res RecordedResponse) *http.Response {
+	header := make(http.Header)
+	for k, v := range res.Headers {
+		header.Set(k, v)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.Status, http.StatusText(res.Status)),
+		StatusCode:    res.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(res.Body)),
+		ContentLength: int64(len(res.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testObjectCreateOptions creates a testObject.
+type testObjectCreateOptions struct {
+	ID       string  `jsonapi:"primary,test-objects"`
+	Name     *string `jsonapi:"attr,name"`
+	Password *string `jsonapi:"attr,password,omitempty"`
+}
+
+// serveTestObjects creates and reads test objects named after the request.
+var serveTestObjects = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	// Neither this header nor the request's credentials may be recorded.
+	w.Header().Set("X-Session-Token", "session-secret")
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/api/fake-resources/test_objects":
+		writeObject(w, http.StatusCreated, "obj-1", "recorded")
+	case r.Method == "GET" && r.URL.Path == "/api/fake-resources/test_objects/obj-1":
+		writeObject(w, http.StatusOK, "obj-1", "recorded")
+	default:
+		http.NotFound(w, r)
+	}
+})
+
+// cassetteRequests sends a create and a read, returning the objects read.
+func cassetteRequests(t *testing.T, c *Client, name string) (*testObject, *testObject, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	req, err := c.NewRequest("POST", "test_objects", &testObjectCreateOptions{
+		Name:     String(name),
+		Password: String("hunter2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := &testObject{}
+	if err := c.Do(ctx, req, created); err != nil {
+		return nil, nil, err
+	}
+
+	req, err = c.NewRequest("GET", "test_objects/obj-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := &testObject{}
+	if err := c.Do(ctx, req, read); err != nil {
+		return nil, nil, err
+	}
+
+	return created, read, nil
+}
+
+func TestCassette_roundTrip(t *testing.T) {
+	for _, ext := range []string{".json", ".yaml"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cassette"+ext)
+			srv := newTestServer(t, serveTestObjects)
+			hostname := strings.TrimPrefix(srv.URL, "https://")
+
+			recorder := newTestClient(t, srv, WithCassette(path, CassetteRecord))
+			if _, _, err := cassetteRequests(t, recorder, "web"); err != nil {
+				t.Fatalf("error recording: %v", err)
+			}
+
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, secret := range []string{"hunter2", "test-token", "Authorization", "Idempotency-Key", "session-secret"} {
+				if strings.Contains(string(raw), secret) {
+					t.Errorf("cassette contains %q:\n%s", secret, raw)
+				}
+			}
+			if !strings.Contains(string(raw), redacted) {
+				t.Errorf("cassette does not show the redacted password:\n%s", raw)
+			}
+
+			// Replay as a new process would, with the API gone.
+			srv.Close()
+			cassettesMu.Lock()
+			delete(cassettes, path)
+			cassettesMu.Unlock()
+
+			replayer, err := NewClient(hostname, "other-token", WithCassette(path, CassetteReplay))
+			if err != nil {
+				t.Fatal(err)
+			}
+			replayer.HTTPClient.Logger = nil
+
+			created, read, err := cassetteRequests(t, replayer, "web")
+			if err != nil {
+				t.Fatalf("error replaying: %v", err)
+			}
+			if created.ID != "obj-1" || read.Name != "recorded" {
+				t.Errorf("got %+v and %+v from the cassette", created, read)
+			}
+
+			// Replays match on the method, path and body.
+			misses := map[string]func() error{
+				"body": func() error {
+					_, _, err := cassetteRequests(t, replayer, "db")
+					return err
+				},
+				"path": func() error {
+					req, _ := replayer.NewRequest("GET", "test_objects/obj-2", nil)
+					return replayer.Do(context.Background(), req, &testObject{})
+				},
+				"method": func() error {
+					req, _ := replayer.NewRequest("DELETE", "test_objects/obj-1", nil)
+					return replayer.Do(context.Background(), req, nil)
+				},
+			}
+			for name, send := range misses {
+				err := send()
+
+				var miss *cassetteMissError
+				if !errors.As(err, &miss) {
+					t.Errorf("different %s: got error %v, want a cassette miss", name, err)
+					continue
+				}
+				// A miss fails at once instead of being retried.
+				if !strings.Contains(err.Error(), "after 1 attempt") {
+					t.Errorf("different %s: miss was retried: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCassette_replayMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+	if _, err := NewClient("example.com", "test-token", WithCassette(path, CassetteReplay)); err == nil {
+		t.Error("expected an error replaying a missing cassette")
+	}
+}
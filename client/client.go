@@ -5,6 +5,8 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,10 +15,15 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/svanharmelen/jsonapi"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 const DefaultHostname = "app.terraform.io"
@@ -34,10 +41,77 @@ type Client struct {
 	Hostname   string
 	HTTPClient *retryablehttp.Client
 	Token      string
+	UserAgent  string
+
+	// batchWindow is how long creates wait to be batched, or zero to
+	// disable batching.
+	batchWindow time.Duration
+
+	// batchers holds a *batcher for each path and type of create that
+	// creates are batched for, keyed by batcherKey.
+	batchers sync.Map
+
+	// readAfterCreateTimeout is how long DoAfterCreate waits for new
+	// objects to become visible.
+	readAfterCreateTimeout time.Duration
+}
+
+// Option configures optional Client behavior.
+type Option func(*options)
+
+type options struct {
+	tlsConfig         *tls.Config
+	userAgent         string
+	maxConcurrent     int
+	requestsPerSecond float64
+	batchWindow       time.Duration
+	cassettePath      string
+	cassetteMode      CassetteMode
+
+	readAfterCreateTimeout *time.Duration
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the API.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(o *options) {
+		o.userAgent = ua
+	}
+}
+
+// WithMaxConcurrentRequests limits how many requests the client has in
+// flight at once. Zero means no limit.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(o *options) {
+		o.maxConcurrent = n
+	}
+}
+
+// WithRequestsPerSecond limits how many requests per second the client
+// sends, retries included. Zero means no limit.
+func WithRequestsPerSecond(r float64) Option {
+	return func(o *options) {
+		o.requestsPerSecond = r
+	}
+}
+
+// WithBatchWindow enables batching in Create: concurrent creates to the
+// same endpoint that arrive within d of each other are sent as one bulk
+// request. Zero disables batching.
+func WithBatchWindow(d time.Duration) Option {
+	return func(o *options) {
+		o.batchWindow = d
+	}
 }
 
 // NewClient -
-func NewClient(hostname, token string) (*Client, error) {
+func NewClient(hostname, token string, opts ...Option) (*Client, error) {
 	baseURL, err := url.Parse("https://" + hostname + "/api/fake-resources/")
 	if err != nil {
 		return nil, fmt.Errorf("invalid hostname: %s", hostname)
@@ -47,11 +121,54 @@ func NewClient(hostname, token string) (*Client, error) {
 		return nil, fmt.Errorf("missing API token")
 	}
 
+	o := &options{
+		userAgent: DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// The pooled transport honors HTTPS_PROXY and friends.
+	transport := cleanhttp.DefaultPooledTransport()
+	if o.tlsConfig != nil {
+		transport.TLSClientConfig = o.tlsConfig
+	}
+
+	var base http.RoundTripper = transport
+	if o.cassettePath == "" {
+		o.cassettePath, o.cassetteMode = cassetteFromEnv()
+	}
+	if o.cassettePath != "" {
+		base, err = newCassetteTransport(transport, o.cassettePath, o.cassetteMode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := retryablehttp.NewClient()
+	httpClient.HTTPClient.Transport = newThrottledTransport(
+		otelhttp.NewTransport(base),
+		o.maxConcurrent,
+		o.requestsPerSecond,
+	)
+	if o.cassettePath != "" {
+		httpClient.CheckRetry = cassetteRetryPolicy
+	}
+	httpClient.RequestLogHook = requestLogHook
+	httpClient.ResponseLogHook = responseLogHook
+
 	c := &Client{
 		BaseURL:    baseURL,
-		HTTPClient: retryablehttp.NewClient(),
+		HTTPClient: httpClient,
 		Hostname:   hostname,
 		Token:      token,
+		UserAgent:  o.userAgent,
+
+		batchWindow:            o.batchWindow,
+		readAfterCreateTimeout: DefaultReadAfterCreateTimeout,
+	}
+	if o.readAfterCreateTimeout != nil {
+		c.readAfterCreateTimeout = *o.readAfterCreateTimeout
 	}
 
 	return c, nil
@@ -64,8 +181,20 @@ func NewClient(hostname, token string) (*Client, error) {
 // If v implements the io.Writer interface, the raw response body will be
 // written to v, without attempting to first decode it.
 //
+// The request is bound to ctx, so it is abandoned (including any pending
+// retries) once ctx is cancelled.
+//
 // This function is ported nearly directly from https://github.com/hashicorp/go-tfe
-func (c *Client) Do(req *retryablehttp.Request, v interface{}) error {
+func (c *Client) Do(ctx context.Context, req *retryablehttp.Request, v interface{}) (err error) {
+	// Trace the request as a whole; each attempt gets a child span from
+	// the transport.
+	ctx, span := c.startSpan(ctx, req)
+	ctx, state := withRequestLogging(ctx)
+	defer func() { endSpan(span, state, err) }()
+
+	// Add the context to the request.
+	req = req.WithContext(ctx)
+
 	// Execute the request and check the response.
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
@@ -147,6 +276,9 @@ func (c *Client) Do(req *retryablehttp.Request, v interface{}) error {
 	return nil
 }
 
+// NewRequest creates an API request for path, relative to the client's
+// BaseURL. For GET requests v may hold ReadOptions or ListOptions to
+// encode into the query string; for other methods it is the request body.
 func (c *Client) NewRequest(method, path string, v interface{}) (*retryablehttp.Request, error) {
 	u, err := c.BaseURL.Parse(path)
 	if err != nil {
@@ -156,15 +288,37 @@ func (c *Client) NewRequest(method, path string, v interface{}) (*retryablehttp.
 	// Create a request specific headers map.
 	reqHeaders := make(http.Header)
 	reqHeaders.Set("Authorization", "Bearer "+c.Token)
+	reqHeaders.Set("User-Agent", c.UserAgent)
 
 	var body interface{}
 	switch method {
 	case "GET":
 		reqHeaders.Set("Accept", "application/vnd.api+json")
+
+		if v != nil {
+			opts, ok := v.(queryEncoder)
+			if !ok {
+				return nil, fmt.Errorf("GET options must be ReadOptions or ListOptions, got %T", v)
+			}
+			q := u.Query()
+			opts.encodeQuery(q)
+			u.RawQuery = q.Encode()
+		}
 	case "DELETE", "PATCH", "POST", "PUT":
 		reqHeaders.Set("Accept", "application/vnd.api+json")
 		reqHeaders.Set("Content-Type", "application/vnd.api+json")
 
+		// Creates aren't naturally idempotent, so tag each one with a key
+		// the API can use to deduplicate. The header lives on the request
+		// itself, so retryablehttp sends the same key on every retry.
+		if method == "POST" {
+			key, err := uuid.GenerateUUID()
+			if err != nil {
+				return nil, err
+			}
+			reqHeaders.Set("Idempotency-Key", key)
+		}
+
 		if v != nil {
 			if body, err = serializeRequestBody(v); err != nil {
 				return nil, err
@@ -285,7 +439,7 @@ func checkResponseCode(r *http.Response) error {
 	errPayload := &jsonapi.ErrorsPayload{}
 	err := json.NewDecoder(r.Body).Decode(errPayload)
 	if err != nil || len(errPayload.Errors) == 0 {
-		return fmt.Errorf(r.Status)
+		return &statusError{status: r.StatusCode, msg: r.Status}
 	}
 
 	// Parse and format the errors.
@@ -298,7 +452,24 @@ func checkResponseCode(r *http.Response) error {
 		}
 	}
 
-	return fmt.Errorf(strings.Join(errs, "\n"))
+	return &statusError{status: r.StatusCode, msg: strings.Join(errs, "\n")}
+}
+
+// statusError is an API error without a sentinel error of its own. It keeps
+// the response status so callers can tell such errors apart.
+type statusError struct {
+	status int
+	msg    string
+}
+
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+// hasStatus reports whether err is an API error with the given status.
+func hasStatus(err error, status int) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.status == status
 }
 
 // String returns a pointer to the given string.
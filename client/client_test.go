@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+// testObject is a model for the objects served in tests.
+type testObject struct {
+	ID   string `jsonapi:"primary,test-objects"`
+	Name string `jsonapi:"attr,name,omitempty"`
+}
+
+// newTestServer starts a TLS server for handler, stopped when the test
+// ends. cfg, if set, is the server's TLS configuration.
+func newTestServer(t *testing.T, handler http.Handler, cfg ...*tls.Config) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewUnstartedServer(handler)
+	if len(cfg) > 0 {
+		srv.TLS = cfg[0]
+	}
+	// Handshake failures are expected in some tests.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestClient returns a client for srv, which it trusts.
+func newTestClient(t *testing.T, srv *httptest.Server, opts ...Option) *Client {
+	t.Helper()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	opts = append([]Option{WithTLSConfig(&tls.Config{RootCAs: roots})}, opts...)
+	c, err := NewClient(strings.TrimPrefix(srv.URL, "https://"), "test-token", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keep retries quick.
+	c.HTTPClient.RetryWaitMin = 0
+	c.HTTPClient.RetryWaitMax = 0
+	c.HTTPClient.Logger = nil
+
+	return c
+}
+
+// writeObject writes a test object as a JSON:API document.
+func writeObject(w http.ResponseWriter, status int, id, name string) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	w.Write([]byte(`{"data": {"type": "test-objects", "id": "` + id + `", "attributes": {"name": "` + name + `"}}}`))
+}
+
+func TestDo_idempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	keys := map[string][]string{}
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		call := r.Method + " " + r.URL.Path
+		keys[call] = append(keys[call], r.Header.Get("Idempotency-Key"))
+
+		// Fail the first attempt of each create, so it is retried.
+		if r.Method == "POST" && len(keys[call])%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeObject(w, http.StatusOK, "obj-1", "one")
+	}))
+	c := newTestClient(t, srv)
+
+	do := func(method, path string, v interface{}) {
+		t.Helper()
+
+		req, err := c.NewRequest(method, path, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Do(context.Background(), req, &testObject{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	do("POST", "test_objects", &testObjectCreateOptions{Name: String("one")})
+	do("POST", "test_objects", &testObjectCreateOptions{Name: String("two")})
+	do("GET", "test_objects/obj-1", nil)
+	do("PATCH", "test_objects/obj-1", &testObjectCreateOptions{Name: String("three")})
+
+	creates := keys["POST /api/fake-resources/test_objects"]
+	if len(creates) != 4 {
+		t.Fatalf("got %d create attempts, want 4", len(creates))
+	}
+	for _, key := range creates {
+		if _, err := uuid.ParseUUID(key); err != nil {
+			t.Errorf("got Idempotency-Key %q, want a UUID", key)
+		}
+	}
+	// Retries reuse their create's key, and each create has its own.
+	if creates[0] != creates[1] || creates[2] != creates[3] {
+		t.Errorf("got keys %v, want each retry to reuse its create's key", creates)
+	}
+	if creates[0] == creates[2] {
+		t.Errorf("got key %q for two creates", creates[0])
+	}
+
+	for _, call := range []string{"GET /api/fake-resources/test_objects/obj-1", "PATCH /api/fake-resources/test_objects/obj-1"} {
+		if got := keys[call]; len(got) != 1 || got[0] != "" {
+			t.Errorf("got Idempotency-Key %q for %s, want none", got, call)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultReadAfterCreateTimeout is how long DoAfterCreate waits for a new
+// object to become visible, unless set WithReadAfterCreateTimeout.
+const DefaultReadAfterCreateTimeout = 30 * time.Second
+
+// Bounds of the delay between DoAfterCreate attempts.
+const (
+	readAfterCreateMinWait = 250 * time.Millisecond
+	readAfterCreateMaxWait = 5 * time.Second
+)
+
+// WithReadAfterCreateTimeout sets how long DoAfterCreate treats a missing
+// object as not yet visible. Zero disables waiting.
+func WithReadAfterCreateTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.readAfterCreateTimeout = &d
+	}
+}
+
+// DoAfterCreate is Do for reading an object that was just created. The API
+// is eventually consistent, so for the client's read-after-create timeout
+// ErrResourceNotFound is treated as transient and the request is retried
+// with backoff. Once the timeout has passed, ErrResourceNotFound is
+// returned as usual.
+func (c *Client) DoAfterCreate(ctx context.Context, req *retryablehttp.Request, v interface{}) error {
+	deadline := time.Now().Add(c.readAfterCreateTimeout)
+	wait := readAfterCreateMinWait
+
+	for attempt := 1; ; attempt++ {
+		err := c.Do(ctx, req, v)
+		if err != ErrResourceNotFound || time.Now().Add(wait).After(deadline) {
+			return err
+		}
+
+		tflog.SubsystemDebug(ctx, logSubsystem, "Object not visible yet after create, retrying", map[string]interface{}{
+			"url":     req.URL.String(),
+			"attempt": attempt,
+			"wait_ms": wait.Milliseconds(),
+		})
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		wait = min(wait*2, readAfterCreateMaxWait)
+	}
+}
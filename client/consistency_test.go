@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// delayedVisibility serves an object that is only found after the first
+// notFound reads, counting the reads.
+type delayedVisibility struct {
+	notFound int32
+	reads    atomic.Int32
+}
+
+func (d *delayedVisibility) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if d.reads.Add(1) <= d.notFound {
+		http.NotFound(w, r)
+		return
+	}
+	writeObject(w, http.StatusOK, "obj-1", "created")
+}
+
+func TestDoAfterCreate(t *testing.T) {
+	cases := map[string]struct {
+		notFound  int32
+		timeout   time.Duration
+		wantErr   error
+		wantReads int32
+	}{
+		"visible within the timeout": {
+			notFound:  2,
+			timeout:   5 * time.Second,
+			wantReads: 3,
+		},
+		"never visible": {
+			notFound: 1000,
+			timeout:  time.Second,
+			wantErr:  ErrResourceNotFound,
+		},
+		"no timeout": {
+			notFound:  1,
+			timeout:   0,
+			wantErr:   ErrResourceNotFound,
+			wantReads: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := &delayedVisibility{notFound: tc.notFound}
+			c := newTestClient(t, newTestServer(t, handler), WithReadAfterCreateTimeout(tc.timeout))
+
+			req, err := c.NewRequest("GET", "test_objects/obj-1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			obj := &testObject{}
+			err = c.DoAfterCreate(context.Background(), req, obj)
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr == nil && obj.Name != "created" {
+				t.Errorf("got name %q, want %q", obj.Name, "created")
+			}
+			if tc.wantReads > 0 && handler.reads.Load() != tc.wantReads {
+				t.Errorf("got %d reads, want %d", handler.reads.Load(), tc.wantReads)
+			}
+
+			// It gives up without waiting past the timeout.
+			if elapsed > tc.timeout+time.Second {
+				t.Errorf("took %s with a timeout of %s", elapsed, tc.timeout)
+			}
+		})
+	}
+}
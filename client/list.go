@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListOptions are the options for listing objects. The zero value lists
+// the first page, at the API's default page size.
+type ListOptions struct {
+	// PageNumber is the page to list, starting at 1.
+	PageNumber int
+	// PageSize is the number of objects per page.
+	PageSize int
+
+	// Filter restricts the list to objects whose attributes match, keyed
+	// by attribute name: {"server-type": "t2.micro"} is sent as
+	// filter[server-type]=t2.micro.
+	Filter map[string]string
+
+	// Sort orders the list by the given attributes, each prefixed with
+	// "-" for descending order.
+	Sort []string
+
+	// Fields limits the attributes returned for each JSON:API type, keyed
+	// by type name.
+	Fields map[string][]string
+
+	// Include lists the relationships whose objects should be returned
+	// alongside each object. See ReadOptions.
+	Include []string
+}
+
+func (o ListOptions) encodeQuery(q url.Values) {
+	if o.PageNumber > 0 {
+		q.Set("page[number]", strconv.Itoa(o.PageNumber))
+	}
+	if o.PageSize > 0 {
+		q.Set("page[size]", strconv.Itoa(o.PageSize))
+	}
+	for k, v := range o.Filter {
+		q.Set("filter["+k+"]", v)
+	}
+	if len(o.Sort) > 0 {
+		q.Set("sort", strings.Join(o.Sort, ","))
+	}
+	for k, v := range o.Fields {
+		q.Set("fields["+k+"]", strings.Join(v, ","))
+	}
+	encodeInclude(q, o.Include)
+}
+
+// page is a page of T, in the shape Do decodes lists into.
+type page[T any] struct {
+	*Pagination
+	Items []T
+}
+
+// List returns one page of the objects at path, along with its pagination
+// details. T must be a pointer to a jsonapi struct, such as *Server.
+func List[T any](ctx context.Context, c *Client, path string, opts *ListOptions) ([]T, *Pagination, error) {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+
+	req, err := c.NewRequest("GET", path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := &page[T]{}
+	if err := c.Do(ctx, req, p); err != nil {
+		return nil, nil, err
+	}
+
+	return p.Items, p.Pagination, nil
+}
+
+// ListAll returns every object at path, following Pagination.NextPage from
+// opts.PageNumber (or the first page) until the last page.
+func ListAll[T any](ctx context.Context, c *Client, path string, opts *ListOptions) ([]T, error) {
+	var all []T
+	for item, err := range All[T](ctx, c, path, opts) {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, item)
+	}
+
+	return all, nil
+}
+
+// All returns an iterator over every object at path, starting from
+// opts.PageNumber (or the first page). Pages are fetched lazily as the
+// caller ranges over them, with the next page prefetched in the background
+// while the current one is consumed. Iteration stops after the first error,
+// which is yielded with the zero T.
+func All[T any](ctx context.Context, c *Client, path string, opts *ListOptions) iter.Seq2[T, error] {
+	o := ListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.PageNumber == 0 {
+		o.PageNumber = 1
+	}
+
+	return func(yield func(T, error) bool) {
+		// Abandon any prefetch if the caller stops early.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		next := fetchPage[T](ctx, c, path, o)
+		for next != nil {
+			res := <-next
+			if res.err != nil {
+				var zero T
+				yield(zero, res.err)
+				return
+			}
+
+			next = nil
+			if res.pagination.NextPage != 0 {
+				// A next page that does not move forward would loop forever.
+				if res.pagination.NextPage <= o.PageNumber {
+					var zero T
+					yield(zero, fmt.Errorf("invalid next page %d after page %d of %s", res.pagination.NextPage, o.PageNumber, path))
+					return
+				}
+				o.PageNumber = res.pagination.NextPage
+				next = fetchPage[T](ctx, c, path, o)
+			}
+
+			for _, item := range res.items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+type pageResult[T any] struct {
+	items      []T
+	pagination *Pagination
+	err        error
+}
+
+// fetchPage lists the page given by opts in the background.
+func fetchPage[T any](ctx context.Context, c *Client, path string, opts ListOptions) <-chan pageResult[T] {
+	ch := make(chan pageResult[T], 1)
+	go func() {
+		items, p, err := List[T](ctx, c, path, &opts)
+		ch <- pageResult[T]{items, p, err}
+	}()
+	return ch
+}
+
+// Collection is a list endpoint returning T, such as "servers".
+type Collection[T any] struct {
+	client *Client
+	path   string
+}
+
+// NewCollection returns the Collection of T at path.
+func NewCollection[T any](c *Client, path string) Collection[T] {
+	return Collection[T]{client: c, path: path}
+}
+
+// List returns one page of the collection. See List.
+func (col Collection[T]) List(ctx context.Context, opts *ListOptions) ([]T, *Pagination, error) {
+	return List[T](ctx, col.client, col.path, opts)
+}
+
+// All returns an iterator over the whole collection. See All.
+func (col Collection[T]) All(ctx context.Context, opts *ListOptions) iter.Seq2[T, error] {
+	return All[T](ctx, col.client, col.path, opts)
+}
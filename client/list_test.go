@@ -0,0 +1,279 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// pagedServer lists count test objects, named obj-1 and up, a page at a
+// time.
+type pagedServer struct {
+	count int
+
+	// nextPage, if set, gives the next page of each page in place of the
+	// real one.
+	nextPage func(page int) int
+
+	mu    sync.Mutex
+	pages []int
+}
+
+func (s *pagedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	number, size := 1, 20
+	if v := r.URL.Query().Get("page[number]"); v != "" {
+		number, _ = strconv.Atoi(v)
+	}
+	if v := r.URL.Query().Get("page[size]"); v != "" {
+		size, _ = strconv.Atoi(v)
+	}
+
+	s.mu.Lock()
+	s.pages = append(s.pages, number)
+	s.mu.Unlock()
+
+	type resource struct {
+		Type       string            `json:"type"`
+		ID         string            `json:"id"`
+		Attributes map[string]string `json:"attributes"`
+	}
+	data := []resource{}
+	for i := (number-1)*size + 1; i <= min(number*size, s.count); i++ {
+		id := fmt.Sprintf("obj-%d", i)
+		data = append(data, resource{Type: "test-objects", ID: id, Attributes: map[string]string{"name": id}})
+	}
+
+	p := Pagination{CurrentPage: number, TotalCount: s.count, TotalPages: (s.count + size - 1) / size}
+	if number > 1 {
+		p.PreviousPage = number - 1
+	}
+	if number < p.TotalPages {
+		p.NextPage = number + 1
+	}
+	if s.nextPage != nil {
+		p.NextPage = s.nextPage(number)
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": data,
+		"meta": map[string]interface{}{"pagination": p},
+	})
+}
+
+// requested returns the pages requested so far, sorted.
+func (s *pagedServer) requested() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Sorted(slices.Values(s.pages))
+}
+
+func objectNames(objs []*testObject) string {
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestAll_pages(t *testing.T) {
+	srv := &pagedServer{count: 5}
+	c := newTestClient(t, newTestServer(t, srv))
+
+	var got []*testObject
+	for obj, err := range All[*testObject](context.Background(), c, "test_objects", &ListOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, obj)
+	}
+
+	if names := objectNames(got); names != "obj-1,obj-2,obj-3,obj-4,obj-5" {
+		t.Errorf("got %s", names)
+	}
+	if pages := srv.requested(); !slices.Equal(pages, []int{1, 2, 3}) {
+		t.Errorf("requested pages %v, want [1 2 3]", pages)
+	}
+}
+
+func TestAll_startPage(t *testing.T) {
+	srv := &pagedServer{count: 5}
+	c := newTestClient(t, newTestServer(t, srv))
+
+	var got []*testObject
+	for obj, err := range All[*testObject](context.Background(), c, "test_objects", &ListOptions{PageNumber: 2, PageSize: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, obj)
+	}
+
+	if names := objectNames(got); names != "obj-3,obj-4,obj-5" {
+		t.Errorf("got %s", names)
+	}
+}
+
+func TestAll_earlyBreak(t *testing.T) {
+	srv := &pagedServer{count: 10}
+	c := newTestClient(t, newTestServer(t, srv))
+
+	var got []*testObject
+	for obj, err := range All[*testObject](context.Background(), c, "test_objects", &ListOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, obj)
+		if len(got) == 3 {
+			break
+		}
+	}
+
+	if names := objectNames(got); names != "obj-1,obj-2,obj-3" {
+		t.Errorf("got %s", names)
+	}
+	// The page being read, and at most the one after it.
+	if pages := srv.requested(); len(pages) > 3 || pages[len(pages)-1] > 3 {
+		t.Errorf("requested pages %v after stopping on page 2", pages)
+	}
+}
+
+func TestAll_cancelled(t *testing.T) {
+	srv := &pagedServer{count: 6}
+	c := newTestClient(t, newTestServer(t, srv))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got int
+	var iterErr error
+	for _, err := range All[*testObject](ctx, c, "test_objects", &ListOptions{PageSize: 2}) {
+		if err != nil {
+			iterErr = err
+			continue
+		}
+		got++
+		cancel()
+	}
+
+	if !errors.Is(iterErr, context.Canceled) {
+		t.Fatalf("got error %v, want %v", iterErr, context.Canceled)
+	}
+	if got >= srv.count {
+		t.Errorf("got all %d objects after cancelling", got)
+	}
+}
+
+func TestAll_nextPageNotAfterCurrent(t *testing.T) {
+	cases := map[string]func(page int) int{
+		"same page":     func(page int) int { return page },
+		"earlier page":  func(page int) int { return page - 1 },
+		"negative page": func(page int) int { return -1 },
+	}
+
+	for name, nextPage := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := &pagedServer{count: 6, nextPage: nextPage}
+			c := newTestClient(t, newTestServer(t, srv))
+
+			var got int
+			var iterErr error
+			for _, err := range All[*testObject](context.Background(), c, "test_objects", &ListOptions{PageNumber: 2, PageSize: 2}) {
+				if err != nil {
+					iterErr = err
+					break
+				}
+				got++
+			}
+
+			if iterErr == nil || !strings.Contains(iterErr.Error(), "invalid next page") {
+				t.Fatalf("got error %v", iterErr)
+			}
+			if got != 0 {
+				t.Errorf("got %d objects, want 0", got)
+			}
+		})
+	}
+}
+
+func TestListOptions_encodeQuery(t *testing.T) {
+	cases := map[string]struct {
+		opts ListOptions
+		want string
+	}{
+		"zero value": {
+			want: "",
+		},
+		"page": {
+			opts: ListOptions{PageNumber: 3, PageSize: 50},
+			want: "page[number]=3&page[size]=50",
+		},
+		"filter": {
+			opts: ListOptions{Filter: map[string]string{"server-type": "t2.micro", "vpc": "main"}},
+			want: "filter[server-type]=t2.micro&filter[vpc]=main",
+		},
+		"sort": {
+			opts: ListOptions{Sort: []string{"name", "-size"}},
+			want: "sort=name,-size",
+		},
+		"fields": {
+			opts: ListOptions{Fields: map[string][]string{"fake-resources-servers": {"name", "type"}}},
+			want: "fields[fake-resources-servers]=name,type",
+		},
+		"include": {
+			opts: ListOptions{Include: []string{"parent-vpc"}},
+			want: "include=parent-vpc",
+		},
+		"everything": {
+			opts: ListOptions{
+				PageNumber: 2,
+				PageSize:   10,
+				Filter:     map[string]string{"name": "web"},
+				Sort:       []string{"-name"},
+			},
+			want: "filter[name]=web&page[number]=2&page[size]=10&sort=-name",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := url.Values{}
+			tc.opts.encodeQuery(q)
+
+			got, err := url.QueryUnescape(q.Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestListAll(t *testing.T) {
+	srv := &pagedServer{count: 7}
+	c := newTestClient(t, newTestServer(t, srv))
+
+	got, err := ListAll[*testObject](context.Background(), c, "test_objects", &ListOptions{PageSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := objectNames(got); names != "obj-1,obj-2,obj-3,obj-4,obj-5,obj-6,obj-7" {
+		t.Errorf("got %s", names)
+	}
+	if pages := srv.requested(); !slices.Equal(pages, []int{1, 2, 3}) {
+		t.Errorf("requested pages %v, want [1 2 3]", pages)
+	}
+}
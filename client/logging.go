@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// logSubsystem is the tflog subsystem used for HTTP traffic. Its level can
+// be set separately with TF_LOG_PROVIDER_FWS_CLIENT.
+const logSubsystem = "fws_client"
+
+// redacted replaces secret values in logs.
+const redacted = "***"
+
+// secretAttributes are JSON:API attributes whose values are never logged.
+var secretAttributes = map[string]bool{
+	"password": true,
+	"token":    true,
+}
+
+// logLevelEnvs are the environment variables setting the level of
+// logSubsystem, most specific first.
+var logLevelEnvs = []string{"TF_LOG_PROVIDER_FWS_CLIENT", "TF_LOG_PROVIDER", "TF_LOG"}
+
+// debugLogging reports whether logSubsystem logs at debug level. tflog
+// cannot say, so this reads the environment variables it is configured
+// from. The hooks use it to skip reading bodies no one will see.
+func debugLogging() bool {
+	for _, env := range logLevelEnvs {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		// Terraform treats TF_LOG=JSON as trace level.
+		if strings.EqualFold(v, "json") {
+			return true
+		}
+		level := hclog.LevelFromString(v)
+		return level != hclog.NoLevel && level <= hclog.Debug
+	}
+	return false
+}
+
+// requestStateKey is the context key for a request's *requestState.
+type requestStateKey struct{}
+
+// requestState is shared by the hooks across the attempts of one request.
+type requestState struct {
+	// start is when the current attempt was sent, so the response hook
+	// can log its latency.
+	start time.Time
+
+	// attempts is the number of attempts made so far.
+	attempts int
+
+	// status is the status code of the latest response.
+	status int
+}
+
+// withRequestLogging prepares ctx for use by the log hooks.
+func withRequestLogging(ctx context.Context) (context.Context, *requestState) {
+	state := &requestState{}
+	ctx = tflog.NewSubsystem(ctx, logSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER", logSubsystem))
+	return context.WithValue(ctx, requestStateKey{}, state), state
+}
+
+// requestLogHook logs every attempt of a request, including retries.
+func requestLogHook(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	ctx := req.Context()
+	if state, ok := ctx.Value(requestStateKey{}).(*requestState); ok {
+		state.start = time.Now()
+		state.attempts = attempt + 1
+	}
+	if !debugLogging() {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"attempt": attempt + 1,
+		"headers": redactHeaders(req.Header),
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			raw, _ := io.ReadAll(body)
+			body.Close()
+			if len(raw) > 0 {
+				fields["body"] = redactBody(raw)
+			}
+		}
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystem, "Sending HTTP request", fields)
+}
+
+// responseLogHook logs every response received, including ones that will
+// be retried.
+func responseLogHook(_ retryablehttp.Logger, resp *http.Response) {
+	ctx := resp.Request.Context()
+	state, _ := ctx.Value(requestStateKey{}).(*requestState)
+	if state != nil {
+		state.status = resp.StatusCode
+	}
+
+	// Reading the body would buffer every response, and release its
+	// throttle slot before the caller is done with it.
+	if !debugLogging() {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"method": resp.Request.Method,
+		"url":    resp.Request.URL.String(),
+		"status": resp.StatusCode,
+	}
+
+	if state != nil && !state.start.IsZero() {
+		fields["latency_ms"] = time.Since(state.start).Milliseconds()
+	}
+
+	// Read the body for logging, then put it back for the caller.
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+	if err == nil && len(raw) > 0 {
+		fields["body"] = redactBody(raw)
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystem, "Received HTTP response", fields)
+}
+
+// redactHeaders returns the headers as a flat map, with credentials
+// redacted.
+func redactHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+	for k, v := range h {
+		if strings.EqualFold(k, "Authorization") {
+			headers[k] = redacted
+			continue
+		}
+		headers[k] = strings.Join(v, ", ")
+	}
+	return headers
+}
+
+// redactBody returns a JSON body as a string with the values of any secret
+// attributes redacted. Bodies that are not JSON are not logged.
+func redactBody(raw []byte) string {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "(non-JSON body omitted)"
+	}
+
+	redactSecrets(doc)
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "(body omitted)"
+	}
+	return string(out)
+}
+
+// redactSecrets replaces the values of secret attributes anywhere in a
+// decoded JSON document.
+func redactSecrets(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if secretAttributes[k] {
+				v[k] = redacted
+				continue
+			}
+			redactSecrets(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactSecrets(child)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+// setLogLevel sets the fws_client log level, clearing the variables it
+// falls back to.
+func setLogLevel(t *testing.T, level string) {
+	for _, env := range logLevelEnvs {
+		t.Setenv(env, "")
+	}
+	t.Setenv("TF_LOG_PROVIDER_FWS_CLIENT", level)
+}
+
+func TestLogging_redactsSecrets(t *testing.T) {
+	setLogLevel(t, "DEBUG")
+
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data": {"type": "test-objects", "id": "obj-1", "attributes": {"name": "db", "token": "response-secret"}}}`))
+	}))
+	c := newTestClient(t, srv)
+
+	var logs bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logs)
+
+	req, err := c.NewRequest("POST", "test_objects", &testObjectCreateOptions{
+		Name:     String("db"),
+		Password: String("hunter2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Do(ctx, req, &testObject{}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := logs.String()
+	for _, want := range []string{"Sending HTTP request", "Received HTTP response", "Authorization", redacted} {
+		if !strings.Contains(out, want) {
+			t.Errorf("logs do not contain %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"hunter2", "test-token", "response-secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("logs contain %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestLogging_offLeavesBody(t *testing.T) {
+	setLogLevel(t, "")
+
+	body := io.NopCloser(strings.NewReader("{}"))
+	req, _ := http.NewRequest("GET", "https://example.com/", nil)
+	resp := &http.Response{StatusCode: http.StatusOK, Body: body, Request: req}
+
+	responseLogHook(nil, resp)
+	if resp.Body != body {
+		t.Error("response body was replaced with debug logging off")
+	}
+}
+
+func TestDebugLogging(t *testing.T) {
+	cases := map[string]struct {
+		env  map[string]string
+		want bool
+	}{
+		"unset":     {want: false},
+		"debug":     {env: map[string]string{"TF_LOG": "DEBUG"}, want: true},
+		"trace":     {env: map[string]string{"TF_LOG_PROVIDER": "trace"}, want: true},
+		"json":      {env: map[string]string{"TF_LOG": "JSON"}, want: true},
+		"info":      {env: map[string]string{"TF_LOG": "INFO"}, want: false},
+		"off":       {env: map[string]string{"TF_LOG": "OFF"}, want: false},
+		"subsystem": {env: map[string]string{"TF_LOG": "DEBUG", "TF_LOG_PROVIDER_FWS_CLIENT": "WARN"}, want: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			for _, env := range logLevelEnvs {
+				t.Setenv(env, tc.env[env])
+			}
+			if got := debugLogging(); got != tc.want {
+				t.Errorf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
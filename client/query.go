@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"net/url"
+	"strings"
+)
+
+// queryEncoder is implemented by the options NewRequest accepts for GET
+// requests, which are encoded into the request's query string.
+type queryEncoder interface {
+	encodeQuery(q url.Values)
+}
+
+// ReadOptions are the options for reading a single object.
+type ReadOptions struct {
+	// Include lists the relationships whose objects should be returned
+	// alongside the primary data, in the same response. They are decoded
+	// into the model's jsonapi "relation" fields.
+	Include []string
+}
+
+func (o ReadOptions) encodeQuery(q url.Values) {
+	encodeInclude(q, o.Include)
+}
+
+func encodeInclude(q url.Values, include []string) {
+	if len(include) > 0 {
+		q.Set("include", strings.Join(include, ","))
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// throttledTransport limits how many requests are in flight at once and
+// how quickly they are sent. Every attempt, including retries, passes
+// through it.
+type throttledTransport struct {
+	next http.RoundTripper
+
+	// limiter paces requests, or is nil for no rate limit.
+	limiter *rate.Limiter
+
+	// sem holds a slot for each request in flight, from when it is sent
+	// until its response body is closed, or is nil for no concurrency
+	// limit.
+	sem chan struct{}
+}
+
+// newThrottledTransport wraps next with the given limits. A limit of zero
+// disables it; if both are zero, next is returned unchanged.
+func newThrottledTransport(next http.RoundTripper, maxConcurrent int, requestsPerSecond float64) http.RoundTripper {
+	if maxConcurrent <= 0 && requestsPerSecond <= 0 {
+		return next
+	}
+
+	t := &throttledTransport{next: next}
+	if maxConcurrent > 0 {
+		t.sem = make(chan struct{}, maxConcurrent)
+	}
+	if requestsPerSecond > 0 {
+		burst := int(math.Max(1, math.Ceil(requestsPerSecond)))
+		t.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+
+	return t
+}
+
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	release := func() {}
+	if t.sem != nil {
+		select {
+		case t.sem <- struct{}{}:
+			var once sync.Once
+			release = func() { once.Do(func() { <-t.sem }) }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	if wait := time.Since(start); wait >= time.Millisecond {
+		tflog.SubsystemDebug(ctx, logSubsystem, "Throttled HTTP request", map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"wait_ms": wait.Milliseconds(),
+		})
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// The response is still being received until its body is closed.
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody is a response body that gives up its request's slot once
+// it is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
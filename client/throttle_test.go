@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// okTransport answers every request with an empty 200.
+var okTransport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+})
+
+// roundTripAsync sends a request through t, returning a channel that
+// receives its result.
+func roundTripAsync(t http.RoundTripper, ctx context.Context) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/", nil)
+		resp, err := t.RoundTrip(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+	return done
+}
+
+func TestThrottledTransport_holdsSlotUntilBodyClosed(t *testing.T) {
+	tr := newThrottledTransport(okTransport, 1, 0)
+
+	req, _ := http.NewRequest("GET", "https://example.com/", nil)
+	first, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := roundTripAsync(tr, context.Background())
+	select {
+	case <-second:
+		t.Fatal("second request sent while the first response's body was open")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	first.Body.Close()
+	select {
+	case err := <-second:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second request not sent after the first response's body was closed")
+	}
+
+	// Closing a body again does not give up another request's slot.
+	first.Body.Close()
+	if err := <-roundTripAsync(tr, context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestThrottledTransport_releasesSlotOnError(t *testing.T) {
+	failing := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	tr := newThrottledTransport(failing, 1, 0)
+
+	for range 2 {
+		select {
+		case err := <-roundTripAsync(tr, context.Background()):
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("request blocked by a failed request's slot")
+		}
+	}
+}
+
+func TestThrottledTransport_cancelWhileWaiting(t *testing.T) {
+	tr := newThrottledTransport(okTransport, 1, 0)
+
+	req, _ := http.NewRequest("GET", "https://example.com/", nil)
+	first, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := <-roundTripAsync(tr, ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSOptions describes how to verify the API's certificate and, for mutual
+// TLS, which certificate to present.
+type TLSOptions struct {
+	// CACertFile is a PEM-encoded CA bundle trusted in addition to the
+	// system roots.
+	CACertFile string
+
+	// InsecureSkipVerify disables verification of the API's certificate.
+	InsecureSkipVerify bool
+
+	// ClientCert and ClientKey are a PEM-encoded certificate and private
+	// key to present to the API. Both or neither must be set.
+	ClientCert string
+	ClientKey  string
+}
+
+// NewTLSConfig builds a TLS configuration from the given options. It
+// returns nil if no options are set, so the transport's defaults apply.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	if opts == (TLSOptions{}) {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CACertFile != "" {
+		pem, err := os.ReadFile(opts.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA certificate file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA certificate file %s", opts.CACertFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (opts.ClientCert == "") != (opts.ClientKey == "") {
+		return nil, errors.New("client certificate and client key must be set together")
+	}
+	if opts.ClientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(opts.ClientCert), []byte(opts.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
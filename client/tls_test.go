@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveObject answers every request with a test object.
+var serveObject = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	writeObject(w, http.StatusOK, "obj-1", "secure")
+})
+
+// newClientCert generates a self-signed client certificate, returning it
+// and its key as PEM.
+func newClientCert(t *testing.T) (certPEM, keyPEM string, cert *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fws-test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certPEM, keyPEM, cert
+}
+
+// writeCACertFile writes the server's certificate to a CA bundle file.
+func writeCACertFile(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// getWithTLSOptions reads a test object from srv using a client configured
+// with opts.
+func getWithTLSOptions(t *testing.T, srv *httptest.Server, opts TLSOptions) error {
+	t.Helper()
+
+	cfg, err := NewTLSConfig(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(strings.TrimPrefix(srv.URL, "https://"), "test-token", WithTLSConfig(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.HTTPClient.RetryMax = 0
+	c.HTTPClient.Logger = nil
+
+	req, err := c.NewRequest("GET", "test_objects/obj-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.Do(context.Background(), req, &testObject{})
+}
+
+func TestNewTLSConfig_serverVerification(t *testing.T) {
+	srv := newTestServer(t, serveObject)
+
+	if err := getWithTLSOptions(t, srv, TLSOptions{}); err == nil {
+		t.Error("expected an error for an untrusted certificate")
+	}
+	if err := getWithTLSOptions(t, srv, TLSOptions{CACertFile: writeCACertFile(t, srv)}); err != nil {
+		t.Errorf("error with ca_cert_file: %v", err)
+	}
+	if err := getWithTLSOptions(t, srv, TLSOptions{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("error with insecure_skip_verify: %v", err)
+	}
+}
+
+func TestNewTLSConfig_mutualTLS(t *testing.T) {
+	certPEM, keyPEM, cert := newClientCert(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	srv := newTestServer(t, serveObject, &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	})
+	caCertFile := writeCACertFile(t, srv)
+
+	err := getWithTLSOptions(t, srv, TLSOptions{
+		CACertFile: caCertFile,
+		ClientCert: certPEM,
+		ClientKey:  keyPEM,
+	})
+	if err != nil {
+		t.Errorf("error with a client certificate: %v", err)
+	}
+
+	if err := getWithTLSOptions(t, srv, TLSOptions{CACertFile: caCertFile}); err == nil {
+		t.Error("expected an error without a client certificate")
+	}
+}
+
+func TestNewTLSConfig_errors(t *testing.T) {
+	certPEM, keyPEM, _ := newClientCert(t)
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		opts    TLSOptions
+		wantErr string
+	}{
+		"cert without key": {
+			opts:    TLSOptions{ClientCert: certPEM},
+			wantErr: "must be set together",
+		},
+		"key without cert": {
+			opts:    TLSOptions{ClientKey: keyPEM},
+			wantErr: "must be set together",
+		},
+		"mismatched key": {
+			opts:    TLSOptions{ClientCert: certPEM, ClientKey: "not a key"},
+			wantErr: "invalid client certificate or key",
+		},
+		"missing CA file": {
+			opts:    TLSOptions{CACertFile: missing},
+			wantErr: "error reading CA certificate file",
+		},
+		"empty CA file": {
+			opts:    TLSOptions{CACertFile: empty},
+			wantErr: "no certificates found",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewTLSConfig(tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("got error %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig_noOptions(t *testing.T) {
+	cfg, err := NewTLSConfig(TLSOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != nil {
+		t.Errorf("got %+v, want nil so the transport's defaults apply", cfg)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName identifies the spans created by this package.
+	tracerName = "github.com/hashicorp/terraform-provider-fakewebservices/client"
+
+	// TraceFileEnv names a file to write spans to as JSON, for use when
+	// no OTLP collector is available.
+	TraceFileEnv = "FWS_TRACE_FILE"
+)
+
+// SetupTracing installs a global OpenTelemetry tracer provider when tracing
+// is enabled. Spans are exported over OTLP/HTTP when
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set,
+// and written to the file named by FWS_TRACE_FILE when that is set.
+//
+// The returned function flushes any remaining spans and must be called
+// before the process exits. When tracing is disabled it does nothing.
+func SetupTracing(ctx context.Context, serviceName, version string) (func(context.Context) error, error) {
+	var opts []sdktrace.TracerProviderOption
+	var closers []func() error
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error creating OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	if path := os.Getenv(TraceFileEnv); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("error opening trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("error creating file trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+		closers = append(closers, f.Close)
+	}
+
+	if len(opts) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace resource: %w", err)
+	}
+	opts = append(opts, sdktrace.WithResource(res))
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		for _, c := range closers {
+			err = errors.Join(err, c())
+		}
+		return err
+	}, nil
+}
+
+// startSpan starts the span covering a request and all of its retries.
+func (c *Client) startSpan(ctx context.Context, req *retryablehttp.Request) (context.Context, trace.Span) {
+	resourceType, operation := c.describeRequest(req)
+
+	return otel.Tracer(tracerName).Start(ctx, fmt.Sprintf("fws.%s %s", operation, resourceType),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("fws.resource_type", resourceType),
+			attribute.String("fws.operation", operation),
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(req.URL.String()),
+		),
+	)
+}
+
+// endSpan records the outcome of a request on its span and ends it.
+func endSpan(span trace.Span, state *requestState, err error) {
+	span.SetAttributes(attribute.Int("fws.attempts", state.attempts))
+	if state.attempts > 1 {
+		span.SetAttributes(attribute.Int("fws.retries", state.attempts-1))
+	}
+	if state.status != 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(state.status))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// describeRequest returns the resource type (the first path segment below
+// the API root, such as "servers") and the operation a request performs.
+func (c *Client) describeRequest(req *retryablehttp.Request) (string, string) {
+	path := strings.TrimPrefix(req.URL.Path, c.BaseURL.Path)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	resourceType := segments[0]
+
+	var operation string
+	switch req.Method {
+	case "GET":
+		operation = "read"
+		if len(segments) == 1 {
+			operation = "list"
+		}
+	case "POST":
+		operation = "create"
+	case "PATCH", "PUT":
+		operation = "update"
+	case "DELETE":
+		operation = "delete"
+	default:
+		operation = strings.ToLower(req.Method)
+	}
+
+	return resourceType, operation
+}
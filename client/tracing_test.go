@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a global tracer provider that records every span
+// until the test ends. It must be called before the client is created.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+		tp.Shutdown(context.Background())
+	})
+
+	return exporter
+}
+
+// requestSpan returns the span named name and the spans of its attempts.
+func requestSpan(t *testing.T, exporter *tracetest.InMemoryExporter, name string) (tracetest.SpanStub, []tracetest.SpanStub) {
+	t.Helper()
+
+	var span *tracetest.SpanStub
+	spans := exporter.GetSpans()
+	for i := range spans {
+		if spans[i].Name == name {
+			span = &spans[i]
+		}
+	}
+	if span == nil {
+		var names []string
+		for _, s := range spans {
+			names = append(names, s.Name)
+		}
+		t.Fatalf("no span named %q in %v", name, names)
+	}
+
+	var attempts []tracetest.SpanStub
+	for _, s := range spans {
+		if s.Parent.SpanID() == span.SpanContext.SpanID() {
+			attempts = append(attempts, s)
+		}
+	}
+	return *span, attempts
+}
+
+// checkAttributes reports the attributes in want that span lacks.
+func checkAttributes(t *testing.T, span tracetest.SpanStub, want ...attribute.KeyValue) {
+	t.Helper()
+
+	got := attribute.NewSet(span.Attributes...)
+	for _, kv := range want {
+		if v, ok := got.Value(kv.Key); !ok || v != kv.Value {
+			t.Errorf("got %s %q, want %q", kv.Key, v.Emit(), kv.Value.Emit())
+		}
+	}
+}
+
+func TestTracing_span(t *testing.T) {
+	exporter := recordSpans(t)
+
+	var traceparent string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		writeObject(w, http.StatusOK, "obj-1", "one")
+	}))
+	c := newTestClient(t, srv)
+
+	req, err := c.NewRequest("GET", "test_objects/obj-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Do(context.Background(), req, &testObject{}); err != nil {
+		t.Fatal(err)
+	}
+
+	span, attempts := requestSpan(t, exporter, "fws.read test_objects")
+	checkAttributes(t, span,
+		attribute.String("fws.resource_type", "test_objects"),
+		attribute.String("fws.operation", "read"),
+		attribute.String("http.request.method", "GET"),
+		attribute.String("url.full", srv.URL+"/api/fake-resources/test_objects/obj-1"),
+		attribute.Int("http.response.status_code", http.StatusOK),
+		attribute.Int("fws.attempts", 1),
+	)
+	if attrs := attribute.NewSet(span.Attributes...); attrs.HasValue("fws.retries") {
+		t.Error("got fws.retries for a request that was not retried")
+	}
+	if span.Status.Code != codes.Unset {
+		t.Errorf("got status %v, want %v", span.Status.Code, codes.Unset)
+	}
+	if len(attempts) != 1 {
+		t.Errorf("got %d attempt spans, want 1", len(attempts))
+	}
+
+	// The trace continues on the server.
+	if !strings.Contains(traceparent, span.SpanContext.TraceID().String()) {
+		t.Errorf("got traceparent %q, want trace %s", traceparent, span.SpanContext.TraceID())
+	}
+}
+
+func TestTracing_retries(t *testing.T) {
+	exporter := recordSpans(t)
+
+	var requests atomic.Int32
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeObject(w, http.StatusCreated, "obj-1", "one")
+	}))
+	c := newTestClient(t, srv)
+
+	req, err := c.NewRequest("POST", "test_objects", &testObjectCreateOptions{Name: String("one")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Do(context.Background(), req, &testObject{}); err != nil {
+		t.Fatal(err)
+	}
+
+	span, attempts := requestSpan(t, exporter, "fws.create test_objects")
+	checkAttributes(t, span,
+		attribute.String("http.request.method", "POST"),
+		attribute.Int("http.response.status_code", http.StatusCreated),
+		attribute.Int("fws.attempts", 3),
+		attribute.Int("fws.retries", 2),
+	)
+	if len(attempts) != 3 {
+		t.Errorf("got %d attempt spans, want 3", len(attempts))
+	}
+}
+
+func TestTracing_error(t *testing.T) {
+	exporter := recordSpans(t)
+
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	c := newTestClient(t, srv)
+
+	req, err := c.NewRequest("DELETE", "test_objects/obj-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Do(context.Background(), req, nil); err != ErrResourceNotFound {
+		t.Fatalf("got error %v, want %v", err, ErrResourceNotFound)
+	}
+
+	span, _ := requestSpan(t, exporter, "fws.delete test_objects")
+	checkAttributes(t, span,
+		attribute.String("fws.operation", "delete"),
+		attribute.Int("http.response.status_code", http.StatusNotFound),
+	)
+	if span.Status.Code != codes.Error || span.Status.Description != ErrResourceNotFound.Error() {
+		t.Errorf("got status %v %q, want an error", span.Status.Code, span.Status.Description)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+		t.Errorf("got events %+v, want the recorded error", span.Events)
+	}
+}
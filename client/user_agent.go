@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultUserAgent is sent when no user agent is configured.
+const DefaultUserAgent = "terraform-provider-fakewebservices"
+
+// UserAgent builds the User-Agent header for the provider, such as
+// "terraform-provider-fakewebservices/0.3.0 Terraform/1.9.0". The
+// contents of TF_APPEND_USER_AGENT and the given suffix are appended when
+// set.
+func UserAgent(providerVersion, terraformVersion, suffix string) string {
+	parts := []string{fmt.Sprintf("%s/%s", DefaultUserAgent, providerVersion)}
+
+	if terraformVersion != "" {
+		parts = append(parts, fmt.Sprintf("Terraform/%s", terraformVersion))
+	}
+	if add := strings.TrimSpace(os.Getenv("TF_APPEND_USER_AGENT")); add != "" {
+		parts = append(parts, add)
+	}
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		parts = append(parts, suffix)
+	}
+
+	return strings.Join(parts, " ")
+}
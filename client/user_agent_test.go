@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	cases := map[string]struct {
+		terraformVersion string
+		suffix           string
+		appendEnv        string
+		want             string
+	}{
+		"provider version only": {
+			want: "terraform-provider-fakewebservices/0.3.0",
+		},
+		"terraform version": {
+			terraformVersion: "1.9.0",
+			want:             "terraform-provider-fakewebservices/0.3.0 Terraform/1.9.0",
+		},
+		"suffix": {
+			terraformVersion: "1.9.0",
+			suffix:           "my-pipeline/2.1",
+			want:             "terraform-provider-fakewebservices/0.3.0 Terraform/1.9.0 my-pipeline/2.1",
+		},
+		"blank suffix": {
+			terraformVersion: "1.9.0",
+			suffix:           "  ",
+			want:             "terraform-provider-fakewebservices/0.3.0 Terraform/1.9.0",
+		},
+		"TF_APPEND_USER_AGENT": {
+			terraformVersion: "1.9.0",
+			appendEnv:        " ci/1.0 ",
+			want:             "terraform-provider-fakewebservices/0.3.0 Terraform/1.9.0 ci/1.0",
+		},
+		"TF_APPEND_USER_AGENT before the suffix": {
+			terraformVersion: "1.9.0",
+			suffix:           "my-pipeline/2.1",
+			appendEnv:        "ci/1.0",
+			want:             "terraform-provider-fakewebservices/0.3.0 Terraform/1.9.0 ci/1.0 my-pipeline/2.1",
+		},
+		"blank TF_APPEND_USER_AGENT": {
+			terraformVersion: "1.9.0",
+			appendEnv:        "   ",
+			want:             "terraform-provider-fakewebservices/0.3.0 Terraform/1.9.0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TF_APPEND_USER_AGENT", tc.appendEnv)
+
+			if got := UserAgent("0.3.0", tc.terraformVersion, tc.suffix); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUserAgent_sent(t *testing.T) {
+	var got string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		writeObject(w, http.StatusOK, "obj-1", "one")
+	}))
+
+	for ua, want := range map[string]string{
+		"":                     DefaultUserAgent,
+		"custom/1.0 extra/2.0": "custom/1.0 extra/2.0",
+	} {
+		var opts []Option
+		if ua != "" {
+			opts = append(opts, WithUserAgent(ua))
+		}
+		c := newTestClient(t, srv, opts...)
+
+		req, err := c.NewRequest("GET", "test_objects/obj-1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Do(context.Background(), req, &testObject{}); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("sent User-Agent %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// attrKind is the JSON type of an attribute.
+type attrKind int
+
+const (
+	kindString attrKind = iota
+	kindInt
+	kindStringList
+	kindStringMap
+)
+
+// attribute describes one attribute of a collection's objects.
+type attribute struct {
+	kind     attrKind
+	required bool
+
+	// writeOnly attributes are accepted but never stored or returned.
+	writeOnly bool
+
+	// validate, if set, checks a value of the right kind.
+	validate func(v interface{}) error
+}
+
+// relationship is an includable relationship of a collection's objects.
+type relationship struct {
+	// target is the path of the related collection.
+	target string
+	toMany bool
+
+	// resolve returns the related objects of obj. s.mu must be held.
+	resolve func(s *store, obj *object) []*object
+}
+
+// collection describes one list endpoint of the API and its objects.
+type collection struct {
+	path     string
+	jsonType string
+	idPrefix string
+
+	attributes    map[string]attribute
+	relationships map[string]relationship
+}
+
+// credentialsPath is the collection database credentials are stored in.
+// They are served under their database, not as a top-level collection.
+const credentialsPath = "database_credentials"
+
+var collections = map[string]*collection{
+	"servers": {
+		path:     "servers",
+		jsonType: "fake-resources-servers",
+		idPrefix: "srv",
+		attributes: map[string]attribute{
+			"name":        {kind: kindString, required: true, validate: notEmpty},
+			"server-type": {kind: kindString, required: true, validate: notEmpty},
+			"vpc":         {kind: kindString},
+			"tags":        {kind: kindStringMap},
+		},
+		relationships: map[string]relationship{
+			"parent-vpc": {
+				target: "vpcs",
+				resolve: func(s *store, obj *object) []*object {
+					return objectsNamed(s, "vpcs", obj.Attributes["vpc"])
+				},
+			},
+		},
+	},
+	"databases": {
+		path:     "databases",
+		jsonType: "fake-resources-databases",
+		idPrefix: "db",
+		attributes: map[string]attribute{
+			"name":     {kind: kindString, required: true, validate: notEmpty},
+			"size":     {kind: kindInt, required: true, validate: atLeastOne},
+			"password": {kind: kindString, writeOnly: true},
+			"tags":     {kind: kindStringMap},
+		},
+	},
+	"load_balancers": {
+		path:     "load_balancers",
+		jsonType: "fake-resources-load-balancers",
+		idPrefix: "lb",
+		attributes: map[string]attribute{
+			"name":    {kind: kindString, required: true, validate: notEmpty},
+			"servers": {kind: kindStringList},
+			"tags":    {kind: kindStringMap},
+		},
+		relationships: map[string]relationship{
+			"attached-servers": {
+				target: "servers",
+				toMany: true,
+				resolve: func(s *store, obj *object) []*object {
+					var related []*object
+					names, _ := obj.Attributes["servers"].([]interface{})
+					for _, name := range names {
+						related = append(related, objectsNamed(s, "servers", name)...)
+					}
+					return related
+				},
+			},
+		},
+	},
+	"vpcs": {
+		path:     "vpcs",
+		jsonType: "fake-resources-vpcs",
+		idPrefix: "vpc",
+		attributes: map[string]attribute{
+			"name":       {kind: kindString, required: true, validate: notEmpty},
+			"cidr_block": {kind: kindString, required: true, validate: isCIDR},
+		},
+	},
+	credentialsPath: {
+		path:     credentialsPath,
+		jsonType: "fake-resources-database-credentials",
+		idPrefix: "cred",
+		attributes: map[string]attribute{
+			"ttl": {kind: kindInt, writeOnly: true, validate: atLeastOne},
+		},
+	},
+}
+
+// objectsNamed returns the objects in the collection at path whose name is
+// name. s.mu must be held.
+func objectsNamed(s *store, path string, name interface{}) []*object {
+	if name == nil || name == "" {
+		return nil
+	}
+
+	var named []*object
+	for _, obj := range s.data.Objects[path] {
+		if obj.Attributes["name"] == name {
+			named = append(named, obj)
+		}
+	}
+	return named
+}
+
+// checkAttributes validates attrs, as sent in a create or update. Null
+// values are treated as absent. Required attributes are only enforced
+// when creating.
+func (c *collection) checkAttributes(attrs map[string]interface{}, create bool) error {
+	for name, v := range attrs {
+		a, ok := c.attributes[name]
+		if !ok {
+			return fmt.Errorf("unknown attribute %q", name)
+		}
+		if v == nil {
+			continue
+		}
+		if err := a.check(v); err != nil {
+			return fmt.Errorf("invalid attribute %q: %w", name, err)
+		}
+	}
+
+	if create {
+		for name, a := range c.attributes {
+			if a.required && attrs[name] == nil {
+				return fmt.Errorf("missing required attribute %q", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a attribute) check(v interface{}) error {
+	switch a.kind {
+	case kindString:
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("must be a string")
+		}
+	case kindInt:
+		n, ok := v.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("must be an integer")
+		}
+	case kindStringList:
+		list, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("must be a list of strings")
+		}
+		for _, item := range list {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("must be a list of strings")
+			}
+		}
+	case kindStringMap:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("must be a map of strings")
+		}
+		for _, item := range m {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("must be a map of strings")
+			}
+		}
+	}
+
+	if a.validate != nil {
+		return a.validate(v)
+	}
+	return nil
+}
+
+func notEmpty(v interface{}) error {
+	if v.(string) == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	return nil
+}
+
+func atLeastOne(v interface{}) error {
+	if v.(float64) < 1 {
+		return fmt.Errorf("must be at least 1")
+	}
+	return nil
+}
+
+func isCIDR(v interface{}) error {
+	if _, err := netip.ParsePrefix(v.(string)); err != nil {
+		return fmt.Errorf("must be a CIDR block: %w", err)
+	}
+	return nil
+}
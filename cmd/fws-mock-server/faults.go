@@ -0,0 +1,274 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	mathrand "math/rand/v2"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault kinds.
+const (
+	// faultError fails the request with Status, 500 by default. With
+	// Limit it fails a burst of requests and then recovers.
+	faultError = "error"
+
+	// faultRateLimit fails the request with a 429 and a Retry-After of
+	// RetryAfter, one second by default.
+	faultRateLimit = "rate_limit"
+
+	// faultSlow delays the request by Delay before handling it.
+	faultSlow = "slow"
+
+	// faultTruncate handles the request, then cuts the response body off
+	// halfway through.
+	faultTruncate = "truncate"
+
+	// faultMalformedError fails the request with Status, 500 by default,
+	// and a body that is not a valid JSON:API error document.
+	faultMalformedError = "malformed_error"
+
+	// faultNotFoundAfterCreate handles a create, then answers reads of the
+	// created objects with a 404 for Duration, 5 seconds by default, like
+	// an eventually consistent API. It only applies to creates.
+	faultNotFoundAfterCreate = "not_found_after_create"
+)
+
+// faultRule injects one kind of fault into the requests it matches.
+type faultRule struct {
+	// Route is a path.Match pattern for the request path below
+	// /api/fake-resources/, such as "servers" or "servers/*". Empty
+	// matches every route.
+	Route string `json:"route"`
+
+	// Method matches the request method. Empty matches every method.
+	Method string `json:"method"`
+
+	// Fault is the kind of fault to inject.
+	Fault string `json:"fault"`
+
+	// Probability is the chance of injecting the fault into a matching
+	// request, from 0 to 1. Zero means always.
+	Probability float64 `json:"probability"`
+
+	// Limit is the most times the fault is injected. Zero means no limit.
+	Limit int `json:"limit"`
+
+	Status     int      `json:"status"`
+	RetryAfter duration `json:"retry_after"`
+	Delay      duration `json:"delay"`
+	Duration   duration `json:"duration"`
+
+	fired int
+}
+
+// duration is a time.Duration read from a string like "1.5s".
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("durations must be strings such as \"2s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+// faults holds the fault rules, and the state of the faults in progress.
+type faults struct {
+	mu    sync.Mutex
+	rules []*faultRule
+
+	// hidden holds when each object created under a
+	// not_found_after_create fault becomes visible.
+	hidden map[string]time.Time
+}
+
+// loadFaults reads fault rules from a JSON file holding a list of rules.
+// An empty path means no faults.
+func loadFaults(name string) (*faults, error) {
+	f := &faults{hidden: map[string]time.Time{}}
+	if name == "" {
+		return f, nil
+	}
+
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading faults: %w", err)
+	}
+	if err := json.Unmarshal(raw, &f.rules); err != nil {
+		return nil, fmt.Errorf("error decoding faults: %w", err)
+	}
+
+	for i, rule := range f.rules {
+		switch rule.Fault {
+		case faultError, faultRateLimit, faultSlow, faultTruncate, faultMalformedError, faultNotFoundAfterCreate:
+		default:
+			return nil, fmt.Errorf("fault rule %d: unknown fault %q", i, rule.Fault)
+		}
+		if _, err := path.Match(rule.Route, ""); err != nil {
+			return nil, fmt.Errorf("fault rule %d: invalid route: %w", i, err)
+		}
+		if rule.Probability < 0 || rule.Probability > 1 {
+			return nil, fmt.Errorf("fault rule %d: probability must be between 0 and 1", i)
+		}
+	}
+
+	return f, nil
+}
+
+// pick returns the first rule that fires for a request, if any.
+func (f *faults) pick(method, route string) *faultRule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, rule := range f.rules {
+		if rule.Method != "" && !strings.EqualFold(rule.Method, method) {
+			continue
+		}
+		if rule.Fault == faultNotFoundAfterCreate && method != http.MethodPost {
+			continue
+		}
+		if rule.Route != "" {
+			if ok, _ := path.Match(rule.Route, route); !ok {
+				continue
+			}
+		}
+		if rule.Limit > 0 && rule.fired >= rule.Limit {
+			continue
+		}
+		if rule.Probability > 0 && mathrand.Float64() >= rule.Probability {
+			continue
+		}
+
+		rule.fired++
+		return rule
+	}
+
+	return nil
+}
+
+// isHidden reports whether the object a read of route is for was created
+// too recently to be visible.
+func (f *faults) isHidden(route string) bool {
+	id := path.Base(route)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	until, ok := f.hidden[id]
+	if ok && time.Now().After(until) {
+		delete(f.hidden, id)
+		return false
+	}
+	return ok
+}
+
+// hide keeps the objects in a create response from being read for d.
+func (f *faults) hide(body []byte, d time.Duration) {
+	resources, _, err := decodeResources(body)
+	if err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	until := time.Now().Add(d)
+	for _, res := range resources {
+		f.hidden[res.ID] = until
+	}
+}
+
+// injectRouteFaults injects the faults configured for each route.
+func (s *server) injectRouteFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := strings.TrimPrefix(r.URL.Path, apiPrefix)
+
+		if r.Method == http.MethodGet && s.faults.isHidden(route) {
+			writeError(w, http.StatusNotFound, "Not found", "The object was created too recently to be visible.")
+			return
+		}
+
+		rule := s.faults.pick(r.Method, route)
+		if rule == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		log.Printf("Injecting %s fault into %s %s", rule.Fault, r.Method, r.URL.Path)
+
+		switch rule.Fault {
+		case faultError:
+			status := rule.statusOr(http.StatusInternalServerError)
+			writeError(w, status, "Injected error", "This error was injected by fws-mock-server.")
+
+		case faultRateLimit:
+			retryAfter := time.Duration(rule.RetryAfter)
+			if retryAfter <= 0 {
+				retryAfter = time.Second
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+			writeError(w, http.StatusTooManyRequests, "Too many requests", "")
+
+		case faultSlow:
+			select {
+			case <-time.After(time.Duration(rule.Delay)):
+			case <-r.Context().Done():
+				return
+			}
+			next.ServeHTTP(w, r)
+
+		case faultTruncate:
+			rec := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			body := rec.body.Bytes()
+			for k, v := range rec.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.status)
+			w.Write(body[:len(body)/2])
+
+		case faultMalformedError:
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(rule.statusOr(http.StatusInternalServerError))
+			w.Write([]byte(`{"errors": "injected error", "detail": {"status": 500`))
+
+		case faultNotFoundAfterCreate:
+			rec := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if r.Method == http.MethodPost && rec.status == http.StatusCreated {
+				d := time.Duration(rule.Duration)
+				if d <= 0 {
+					d = 5 * time.Second
+				}
+				s.faults.hide(rec.body.Bytes(), d)
+			}
+			for k, v := range rec.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+		}
+	})
+}
+
+func (r *faultRule) statusOr(status int) int {
+	if r.Status != 0 {
+		return r.Status
+	}
+	return status
+}
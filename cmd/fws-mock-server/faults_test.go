@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+	"github.com/hashicorp/terraform-provider-fakewebservices/fws"
+)
+
+// client returns an API client for the mock API, which it trusts.
+func (a *testAPI) client(opts ...client.Option) *client.Client {
+	a.t.Helper()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(a.srv.Certificate())
+
+	opts = append([]client.Option{client.WithTLSConfig(&tls.Config{RootCAs: roots})}, opts...)
+	c, err := client.NewClient(strings.TrimPrefix(a.srv.URL, "https://"), "test-token", opts...)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+
+	// Keep retries quick.
+	c.HTTPClient.RetryWaitMin = 0
+	c.HTTPClient.RetryWaitMax = 0
+	c.HTTPClient.Logger = nil
+
+	return c
+}
+
+// readVpc reads the demo VPC.
+func readVpc(c *client.Client) (*fws.Vpc, error) {
+	req, err := c.NewRequest("GET", "vpcs/vpc-demo", nil)
+	if err != nil {
+		return nil, err
+	}
+	vpc := &fws.Vpc{}
+	return vpc, c.Do(context.Background(), req, vpc)
+}
+
+func TestFaults_errorBurstIsRetried(t *testing.T) {
+	rule := &faultRule{Route: "vpcs/*", Fault: faultError, Status: http.StatusServiceUnavailable, Limit: 2}
+	api := newTestAPI(t, rule)
+
+	vpc, err := readVpc(api.client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpc.Name != "Demo VPC" {
+		t.Errorf("got %+v", vpc)
+	}
+	if rule.fired != 2 {
+		t.Errorf("fault fired %d times, want 2", rule.fired)
+	}
+}
+
+func TestFaults_errorGivesUp(t *testing.T) {
+	api := newTestAPI(t, &faultRule{Fault: faultError})
+	c := api.client()
+	c.HTTPClient.RetryMax = 2
+
+	if _, err := readVpc(c); err == nil || !strings.Contains(err.Error(), "giving up after 3 attempt(s)") {
+		t.Fatalf("got error %v", err)
+	}
+}
+
+func TestFaults_errorIsParsed(t *testing.T) {
+	api := newTestAPI(t, &faultRule{Fault: faultError, Status: http.StatusBadRequest})
+
+	_, err := readVpc(api.client())
+	if err == nil || !strings.Contains(err.Error(), "Injected error\n\nThis error was injected by fws-mock-server.") {
+		t.Fatalf("got error %v", err)
+	}
+}
+
+func TestFaults_rateLimitIsRetried(t *testing.T) {
+	rule := &faultRule{Fault: faultRateLimit, RetryAfter: duration(time.Second), Limit: 1}
+	api := newTestAPI(t, rule)
+
+	start := time.Now()
+	if _, err := readVpc(api.client()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, before the Retry-After", elapsed)
+	}
+	if rule.fired != 1 {
+		t.Errorf("fault fired %d times, want 1", rule.fired)
+	}
+}
+
+func TestFaults_slow(t *testing.T) {
+	delay := 200 * time.Millisecond
+	api := newTestAPI(t, &faultRule{Fault: faultSlow, Delay: duration(delay)})
+
+	start := time.Now()
+	if _, err := readVpc(api.client()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("got a response after %s, want at least %s", elapsed, delay)
+	}
+
+	// A request that times out is abandoned.
+	c := api.client()
+	c.HTTPClient.RetryMax = 0
+	ctx, cancel := context.WithTimeout(context.Background(), delay/4)
+	defer cancel()
+	req, err := c.NewRequest("GET", "vpcs/vpc-demo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Do(ctx, req, &fws.Vpc{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFaults_truncate(t *testing.T) {
+	api := newTestAPI(t, &faultRule{Fault: faultTruncate})
+
+	if _, err := readVpc(api.client()); err == nil {
+		t.Fatal("decoded a truncated response")
+	}
+}
+
+func TestFaults_malformedError(t *testing.T) {
+	api := newTestAPI(t, &faultRule{Fault: faultMalformedError, Status: http.StatusConflict})
+
+	// The error falls back to the response status.
+	_, err := readVpc(api.client())
+	if err == nil || err.Error() != "409 Conflict" {
+		t.Fatalf("got error %v, want 409 Conflict", err)
+	}
+}
+
+func TestFaults_notFoundAfterCreate(t *testing.T) {
+	hidden := 300 * time.Millisecond
+	api := newTestAPI(t, &faultRule{Route: "vpcs", Fault: faultNotFoundAfterCreate, Duration: duration(hidden)})
+	ctx := context.Background()
+
+	read := func(c *client.Client, id string, doAfterCreate bool) error {
+		req, err := c.NewRequest("GET", "vpcs/"+id, nil)
+		if err != nil {
+			return err
+		}
+		if doAfterCreate {
+			return c.DoAfterCreate(ctx, req, &fws.Vpc{})
+		}
+		return c.Do(ctx, req, &fws.Vpc{})
+	}
+
+	c := api.client()
+	opts := &fws.VpcCreateOptions{Name: client.String("new"), CidrBlock: client.String("10.1.0.0/16")}
+
+	// A plain read right after the create misses the object.
+	vpc, err := client.Create[*fws.VpcCreateOptions, *fws.Vpc](ctx, c, "vpcs", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := read(c, vpc.ID, false); err != client.ErrResourceNotFound {
+		t.Fatalf("got error %v, want %v", err, client.ErrResourceNotFound)
+	}
+
+	// DoAfterCreate waits for the object to become visible.
+	vpc, err = client.Create[*fws.VpcCreateOptions, *fws.Vpc](ctx, c, "vpcs", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := read(c, vpc.ID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unless it gives up first.
+	impatient := api.client(client.WithReadAfterCreateTimeout(hidden / 10))
+	vpc, err = client.Create[*fws.VpcCreateOptions, *fws.Vpc](ctx, impatient, "vpcs", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := read(impatient, vpc.ID, true); err != client.ErrResourceNotFound {
+		t.Fatalf("got error %v, want %v", err, client.ErrResourceNotFound)
+	}
+}
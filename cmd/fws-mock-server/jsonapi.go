@@ -0,0 +1,356 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// document is a JSON:API top-level document.
+type document struct {
+	Data     interface{}       `json:"data"`
+	Included []*resourceObject `json:"included,omitempty"`
+	Meta     interface{}       `json:"meta,omitempty"`
+}
+
+// resourceObject is a JSON:API resource object.
+type resourceObject struct {
+	Type          string                          `json:"type"`
+	ID            string                          `json:"id,omitempty"`
+	Attributes    map[string]interface{}          `json:"attributes,omitempty"`
+	Relationships map[string]relationshipDocument `json:"relationships,omitempty"`
+}
+
+type relationshipDocument struct {
+	Data interface{} `json:"data"`
+}
+
+type identifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type errorObject struct {
+	Status string `json:"status"`
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type errorsDocument struct {
+	Errors []errorObject `json:"errors"`
+}
+
+type paginationMeta struct {
+	Pagination pagination `json:"pagination"`
+}
+
+type pagination struct {
+	CurrentPage  int `json:"current-page"`
+	PreviousPage int `json:"prev-page,omitempty"`
+	NextPage     int `json:"next-page,omitempty"`
+	TotalPages   int `json:"total-pages"`
+	TotalCount   int `json:"total-count"`
+}
+
+// requestDocument is a create or update request body, holding a single
+// resource object or, for bulk creates, a list of them.
+type requestDocument struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// decodeResources returns the resource objects in a request body, and
+// whether it held a list.
+func decodeResources(body []byte) ([]*resourceObject, bool, error) {
+	var doc requestDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, false, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	data := strings.TrimSpace(string(doc.Data))
+	switch {
+	case data == "" || data == "null":
+		return nil, false, fmt.Errorf("missing primary data")
+	case strings.HasPrefix(data, "["):
+		var resources []*resourceObject
+		if err := json.Unmarshal(doc.Data, &resources); err != nil {
+			return nil, false, fmt.Errorf("invalid primary data: %w", err)
+		}
+		return resources, true, nil
+	default:
+		resource := &resourceObject{}
+		if err := json.Unmarshal(doc.Data, resource); err != nil {
+			return nil, false, fmt.Errorf("invalid primary data: %w", err)
+		}
+		return []*resourceObject{resource}, false, nil
+	}
+}
+
+// query holds the JSON:API query parameters of a request.
+type query struct {
+	filter  map[string]string
+	sort    []string
+	fields  map[string][]string
+	include [][]string
+
+	pageNumber int
+	pageSize   int
+}
+
+func parseQuery(v url.Values) (*query, error) {
+	q := &query{
+		filter:     map[string]string{},
+		fields:     map[string][]string{},
+		pageNumber: 1,
+		pageSize:   defaultPageSize,
+	}
+
+	for key, values := range v {
+		value := values[0]
+		switch {
+		case key == "page[number]":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("page[number] must be a positive integer")
+			}
+			q.pageNumber = n
+		case key == "page[size]":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("page[size] must be a positive integer")
+			}
+			q.pageSize = min(n, maxPageSize)
+		case key == "sort":
+			q.sort = strings.Split(value, ",")
+		case key == "include":
+			for _, path := range strings.Split(value, ",") {
+				q.include = append(q.include, strings.Split(path, "."))
+			}
+		case strings.HasPrefix(key, "filter[") && strings.HasSuffix(key, "]"):
+			q.filter[key[len("filter["):len(key)-1]] = value
+		case strings.HasPrefix(key, "fields[") && strings.HasSuffix(key, "]"):
+			q.fields[key[len("fields["):len(key)-1]] = strings.Split(value, ",")
+		default:
+			return nil, fmt.Errorf("unsupported query parameter %q", key)
+		}
+	}
+
+	return q, nil
+}
+
+// matches reports whether obj passes every filter. List attributes match
+// if any element does.
+func (q *query) matches(obj *object) bool {
+	for name, want := range q.filter {
+		var v interface{} = obj.ID
+		if name != "id" {
+			v = obj.Attributes[name]
+		}
+
+		if list, ok := v.([]interface{}); ok {
+			if !slices.ContainsFunc(list, func(item interface{}) bool { return formatValue(item) == want }) {
+				return false
+			}
+			continue
+		}
+		if formatValue(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// sortObjects sorts objs by the query's sort fields.
+func (q *query) sortObjects(objs []*object) {
+	if len(q.sort) == 0 {
+		return
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		for _, field := range q.sort {
+			desc := strings.HasPrefix(field, "-")
+			name := strings.TrimPrefix(field, "-")
+
+			c := compareValues(attributeOrID(objs[i], name), attributeOrID(objs[j], name))
+			if c == 0 {
+				continue
+			}
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// page returns the requested page of objs and its pagination details.
+func (q *query) page(objs []*object) ([]*object, pagination) {
+	total := len(objs)
+	p := pagination{
+		CurrentPage: q.pageNumber,
+		TotalPages:  max(1, (total+q.pageSize-1)/q.pageSize),
+		TotalCount:  total,
+	}
+	if p.CurrentPage > 1 {
+		p.PreviousPage = p.CurrentPage - 1
+	}
+	if p.CurrentPage < p.TotalPages {
+		p.NextPage = p.CurrentPage + 1
+	}
+
+	start := min((q.pageNumber-1)*q.pageSize, total)
+	end := min(start+q.pageSize, total)
+
+	return objs[start:end], p
+}
+
+func attributeOrID(obj *object, name string) interface{} {
+	if name == "id" {
+		return obj.ID
+	}
+	return obj.Attributes[name]
+}
+
+func formatValue(v interface{}) string {
+	if n, ok := v.(float64); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func compareValues(a, b interface{}) int {
+	an, aok := a.(float64)
+	bn, bok := b.(float64)
+	if aok && bok {
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(formatValue(a), formatValue(b))
+}
+
+// encoder builds response documents, resolving the requested includes.
+// Its store's mutex must be held while it is used.
+type encoder struct {
+	store *store
+	query *query
+
+	included map[identifier]bool
+	doc      *document
+}
+
+func newEncoder(s *store, q *query) *encoder {
+	return &encoder{
+		store:    s,
+		query:    q,
+		included: map[identifier]bool{},
+		doc:      &document{},
+	}
+}
+
+// one sets the document's primary data to obj.
+func (e *encoder) one(c *collection, obj *object) *document {
+	e.doc.Data = e.resource(c, obj, e.query.include)
+	return e.doc
+}
+
+// many sets the document's primary data to objs.
+func (e *encoder) many(c *collection, objs []*object) *document {
+	data := make([]*resourceObject, 0, len(objs))
+	for _, obj := range objs {
+		data = append(data, e.resource(c, obj, e.query.include))
+	}
+	e.doc.Data = data
+	return e.doc
+}
+
+// resource encodes obj, adding the objects on each include path to the
+// document's included resources.
+func (e *encoder) resource(c *collection, obj *object, include [][]string) *resourceObject {
+	ro := &resourceObject{
+		Type:       c.jsonType,
+		ID:         obj.ID,
+		Attributes: e.attributes(c, obj),
+	}
+
+	// Group the include paths by their first relationship.
+	nested := map[string][][]string{}
+	for _, path := range include {
+		if len(path) > 0 {
+			nested[path[0]] = append(nested[path[0]], path[1:])
+		}
+	}
+
+	for name, rest := range nested {
+		rel, ok := c.relationships[name]
+		if !ok {
+			continue
+		}
+		target := collections[rel.target]
+
+		var ids []identifier
+		for _, related := range rel.resolve(e.store, obj) {
+			id := identifier{Type: target.jsonType, ID: related.ID}
+			ids = append(ids, id)
+
+			if !e.included[id] {
+				e.included[id] = true
+				e.doc.Included = append(e.doc.Included, e.resource(target, related, rest))
+			}
+		}
+
+		if ro.Relationships == nil {
+			ro.Relationships = map[string]relationshipDocument{}
+		}
+		switch {
+		case rel.toMany:
+			if ids == nil {
+				ids = []identifier{}
+			}
+			ro.Relationships[name] = relationshipDocument{Data: ids}
+		case len(ids) > 0:
+			ro.Relationships[name] = relationshipDocument{Data: ids[0]}
+		default:
+			ro.Relationships[name] = relationshipDocument{Data: nil}
+		}
+	}
+
+	return ro
+}
+
+// attributes returns the attributes of obj to send, leaving out write-only
+// attributes and any excluded by a sparse fieldset.
+func (e *encoder) attributes(c *collection, obj *object) map[string]interface{} {
+	fields, sparse := e.query.fields[c.jsonType]
+
+	attrs := map[string]interface{}{}
+	for name, v := range obj.Attributes {
+		if c.attributes[name].writeOnly {
+			continue
+		}
+		if sparse && !slices.Contains(fields, name) {
+			continue
+		}
+		attrs[name] = v
+	}
+	return attrs
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command fws-mock-server serves a local stand-in for the Fake Web Services
+// API, so the provider can be used without Terraform Cloud. Point the
+// provider at it with:
+//
+//	provider "fakewebservices" {
+//	  hostname     = "localhost:8080"
+//	  token        = "anything"
+//	  ca_cert_file = "fws-mock-cert.pem"
+//	}
+//
+// State is kept in a JSON file, so objects survive restarts.
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	var (
+		addr       = flag.String("addr", "localhost:8080", "address to listen on")
+		statePath  = flag.String("state", "fws-mock-state.json", "file to persist state to; empty keeps state in memory")
+		seed       = flag.String("seed", "", `data to start from when there is no state yet: "demo" for built-in demo data, or a JSON file in the state file's format`)
+		token      = flag.String("token", os.Getenv("FWS_MOCK_TOKEN"), "the only API token to accept; by default any token is accepted")
+		latency    = flag.Duration("latency", 0, "latency added to every request")
+		jitter     = flag.Duration("latency-jitter", 0, "up to this much more latency, chosen at random per request")
+		errorRate  = flag.Float64("error-rate", 0, "fraction of requests to fail with a 500, from 0 to 1")
+		faultsPath = flag.String("faults", "", "JSON file of fault rules to inject per route")
+		tlsCert    = flag.String("tls-cert", "", "PEM certificate to serve; by default a self-signed certificate for localhost is generated")
+		tlsKey     = flag.String("tls-key", "", "PEM private key for -tls-cert")
+		certOut    = flag.String("cert-out", "fws-mock-cert.pem", "where to write the generated certificate, for the provider's ca_cert_file")
+	)
+	flag.Parse()
+
+	if *errorRate < 0 || *errorRate > 1 {
+		log.Fatal("-error-rate must be between 0 and 1")
+	}
+
+	seedData, err := loadSeed(*seed)
+	if err != nil {
+		log.Fatalf("error reading seed: %v", err)
+	}
+
+	store, err := openStore(*statePath, seedData)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	faults, err := loadFaults(*faultsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Listen before writing the certificate, so a second server on the
+	// same port fails without replacing the first one's certificate.
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cert, err := loadOrGenerateCertificate(*tlsCert, *tlsKey, *certOut)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv := newServer(config{
+		token:     *token,
+		latency:   *latency,
+		jitter:    *jitter,
+		errorRate: *errorRate,
+	}, store, faults)
+
+	httpServer := &http.Server{
+		Handler:           srv.handler(),
+		TLSConfig:         &tls.Config{Certificates: []tls.Certificate{cert}},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Serving the Fake Web Services API on https://%s%s", *addr, apiPrefix)
+	log.Fatal(httpServer.ServeTLS(ln, "", ""))
+}
+
+// loadOrGenerateCertificate loads the given certificate and key or, if
+// none is given, generates a self-signed certificate for localhost and
+// writes it to certOut.
+func loadOrGenerateCertificate(certFile, keyFile, certOut string) (tls.Certificate, error) {
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("error loading TLS certificate: %w", err)
+		}
+		return cert, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "fws-mock-server"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	if certOut != "" {
+		certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+		if err := os.WriteFile(certOut, certPEM, 0o644); err != nil {
+			return tls.Certificate{}, fmt.Errorf("error writing certificate: %w", err)
+		}
+		log.Printf("Wrote the server's self-signed certificate to %s", certOut)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
@@ -0,0 +1,613 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	mathrand "math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiPrefix = "/api/fake-resources/"
+
+	// defaultCredentialsTTL is how long database credentials last when the
+	// request does not say.
+	defaultCredentialsTTL = time.Hour
+
+	// idempotencyTTL is how long a create's response is kept for replay.
+	idempotencyTTL = 24 * time.Hour
+)
+
+// config configures the mock API.
+type config struct {
+	// token, if set, is the only bearer token accepted. Otherwise any
+	// non-empty token is.
+	token string
+
+	// latency is added to every request, plus up to jitter more.
+	latency time.Duration
+	jitter  time.Duration
+
+	// errorRate is the fraction of requests failed with a 500.
+	errorRate float64
+}
+
+// server is the mock API.
+type server struct {
+	config config
+	store  *store
+	faults *faults
+
+	idempotencyMu sync.Mutex
+	idempotency   map[string]*cachedResponse
+	inflight      map[string]chan struct{}
+}
+
+type cachedResponse struct {
+	// requestHash is the hash of the request body the response was for.
+	requestHash [sha256.Size]byte
+
+	status  int
+	header  http.Header
+	body    []byte
+	created time.Time
+}
+
+func newServer(cfg config, s *store, f *faults) *server {
+	return &server{
+		config:      cfg,
+		store:       s,
+		faults:      f,
+		idempotency: map[string]*cachedResponse{},
+		inflight:    map[string]chan struct{}{},
+	}
+}
+
+// handler returns the mock API's HTTP handler.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+apiPrefix+"{collection}", s.list)
+	mux.HandleFunc("POST "+apiPrefix+"{collection}", s.create)
+	mux.HandleFunc("GET "+apiPrefix+"{collection}/{id}", s.read)
+	mux.HandleFunc("PATCH "+apiPrefix+"{collection}/{id}", s.update)
+	mux.HandleFunc("DELETE "+apiPrefix+"{collection}/{id}", s.delete)
+	mux.HandleFunc("POST "+apiPrefix+"databases/{id}/credentials", s.createCredentials)
+	mux.HandleFunc("DELETE "+apiPrefix+"databases/{id}/credentials/{credentials}", s.deleteCredentials)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "Not found", fmt.Sprintf("No route for %s %s.", r.Method, r.URL.Path))
+	})
+
+	var h http.Handler = mux
+	h = s.idempotent(h)
+	h = s.injectRouteFaults(h)
+	h = s.injectFaults(h)
+	h = s.authenticate(h)
+	h = logRequests(h)
+	return h
+}
+
+// logRequests logs each request with its status and duration.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// authenticate rejects requests without an acceptable bearer token.
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || (s.config.token != "" && token != s.config.token) {
+			writeError(w, http.StatusUnauthorized, "Unauthorized", "")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// injectFaults delays requests and fails a fraction of them, as configured.
+func (s *server) injectFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delay := s.config.latency
+		if s.config.jitter > 0 {
+			delay += mathrand.N(s.config.jitter)
+		}
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if s.config.errorRate > 0 && mathrand.Float64() < s.config.errorRate {
+			writeError(w, http.StatusInternalServerError, "Injected error", "This error was injected by fws-mock-server.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// idempotent replays the original response to a POST repeating an earlier
+// request's Idempotency-Key, instead of creating its objects again. A key
+// reused with a different body is rejected.
+func (s *server) idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request", err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash := sha256.Sum256(body)
+
+		// Keys are scoped to the caller and endpoint.
+		key = strings.Join([]string{r.Header.Get("Authorization"), r.URL.Path, key}, "\n")
+
+		for {
+			s.idempotencyMu.Lock()
+			if cached, ok := s.idempotency[key]; ok && time.Since(cached.created) < idempotencyTTL {
+				s.idempotencyMu.Unlock()
+				if cached.requestHash != requestHash {
+					writeError(w, http.StatusUnprocessableEntity, "Idempotency key reused",
+						"The Idempotency-Key was already used for a request with a different body.")
+					return
+				}
+				for k, v := range cached.header {
+					w.Header()[k] = v
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(cached.status)
+				w.Write(cached.body)
+				return
+			}
+
+			// Wait for a concurrent request with the same key to finish.
+			if wait, ok := s.inflight[key]; ok {
+				s.idempotencyMu.Unlock()
+				<-wait
+				continue
+			}
+
+			done := make(chan struct{})
+			s.inflight[key] = done
+			s.pruneIdempotencyLocked()
+			s.idempotencyMu.Unlock()
+
+			rec := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			s.idempotencyMu.Lock()
+			if rec.status >= 200 && rec.status <= 299 {
+				s.idempotency[key] = &cachedResponse{
+					requestHash: requestHash,
+					status:      rec.status,
+					header:      rec.header,
+					body:        rec.body.Bytes(),
+					created:     time.Now(),
+				}
+			}
+			delete(s.inflight, key)
+			close(done)
+			s.idempotencyMu.Unlock()
+
+			for k, v := range rec.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+			return
+		}
+	})
+}
+
+// pruneIdempotencyLocked forgets expired responses. s.idempotencyMu must be
+// held.
+func (s *server) pruneIdempotencyLocked() {
+	for key, cached := range s.idempotency {
+		if time.Since(cached.created) >= idempotencyTTL {
+			delete(s.idempotency, key)
+		}
+	}
+}
+
+// responseRecorder buffers a response so it can be cached.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header         { return r.header }
+func (r *responseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *responseRecorder) WriteHeader(status int)      { r.status = status }
+
+// lookupCollection returns the collection named in the request path, or
+// writes a 404 and returns nil.
+func lookupCollection(w http.ResponseWriter, r *http.Request) *collection {
+	c, ok := collections[r.PathValue("collection")]
+	if !ok || c.path == credentialsPath {
+		writeError(w, http.StatusNotFound, "Not found", fmt.Sprintf("Unknown collection %q.", r.PathValue("collection")))
+		return nil
+	}
+	return c
+}
+
+// parseRequestQuery parses the request's query parameters, or writes a 400
+// and returns nil.
+func parseRequestQuery(w http.ResponseWriter, r *http.Request) *query {
+	q, err := parseQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid query", err.Error())
+		return nil
+	}
+	return q
+}
+
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	c := lookupCollection(w, r)
+	if c == nil {
+		return
+	}
+	q := parseRequestQuery(w, r)
+	if q == nil {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	var objs []*object
+	for _, obj := range s.store.data.Objects[c.path] {
+		if q.matches(obj) {
+			objs = append(objs, obj)
+		}
+	}
+	q.sortObjects(objs)
+	objs, p := q.page(objs)
+
+	doc := newEncoder(s.store, q).many(c, objs)
+	doc.Meta = paginationMeta{Pagination: p}
+	writeDocument(w, http.StatusOK, doc)
+}
+
+func (s *server) read(w http.ResponseWriter, r *http.Request) {
+	c := lookupCollection(w, r)
+	if c == nil {
+		return
+	}
+	q := parseRequestQuery(w, r)
+	if q == nil {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	obj, _ := s.store.findLocked(c.path, r.PathValue("id"))
+	if obj == nil {
+		writeNotFound(w, c, r.PathValue("id"))
+		return
+	}
+
+	writeDocument(w, http.StatusOK, newEncoder(s.store, q).one(c, obj))
+}
+
+// create creates one object, or several from a bulk request whose primary
+// data is a list. A bulk create is all or nothing.
+func (s *server) create(w http.ResponseWriter, r *http.Request) {
+	c := lookupCollection(w, r)
+	if c == nil {
+		return
+	}
+
+	resources, bulk, ok := readResources(w, r, c)
+	if !ok {
+		return
+	}
+	for _, res := range resources {
+		if err := c.checkAttributes(res.Attributes, true); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid "+c.path, err.Error())
+			return
+		}
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	created := make([]*object, 0, len(resources))
+	for _, res := range resources {
+		obj := &object{
+			ID:         newID(c),
+			Attributes: map[string]interface{}{},
+			CreatedAt:  time.Now().UTC(),
+		}
+		setAttributes(c, obj, res.Attributes)
+		created = append(created, obj)
+	}
+	s.store.data.Objects[c.path] = append(s.store.data.Objects[c.path], created...)
+
+	if err := s.store.saveLocked(); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error saving state", err.Error())
+		return
+	}
+
+	e := newEncoder(s.store, &query{})
+	if bulk {
+		writeDocument(w, http.StatusCreated, e.many(c, created))
+		return
+	}
+	writeDocument(w, http.StatusCreated, e.one(c, created[0]))
+}
+
+func (s *server) update(w http.ResponseWriter, r *http.Request) {
+	c := lookupCollection(w, r)
+	if c == nil {
+		return
+	}
+
+	resources, bulk, ok := readResources(w, r, c)
+	if !ok {
+		return
+	}
+	if bulk || len(resources) != 1 {
+		writeError(w, http.StatusBadRequest, "Invalid request", "Updates take a single resource object.")
+		return
+	}
+	res := resources[0]
+	if res.ID != "" && res.ID != r.PathValue("id") {
+		writeError(w, http.StatusConflict, "Conflict", "The resource ID does not match the URL.")
+		return
+	}
+	if err := c.checkAttributes(res.Attributes, false); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid "+c.path, err.Error())
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	obj, _ := s.store.findLocked(c.path, r.PathValue("id"))
+	if obj == nil {
+		writeNotFound(w, c, r.PathValue("id"))
+		return
+	}
+	setAttributes(c, obj, res.Attributes)
+
+	if err := s.store.saveLocked(); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error saving state", err.Error())
+		return
+	}
+
+	writeDocument(w, http.StatusOK, newEncoder(s.store, &query{}).one(c, obj))
+}
+
+func (s *server) delete(w http.ResponseWriter, r *http.Request) {
+	c := lookupCollection(w, r)
+	if c == nil {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	id := r.PathValue("id")
+	_, i := s.store.findLocked(c.path, id)
+	if i < 0 {
+		writeNotFound(w, c, id)
+		return
+	}
+	s.store.data.Objects[c.path] = slices.Delete(s.store.data.Objects[c.path], i, i+1)
+
+	// A database's credentials go with it.
+	if c.path == "databases" {
+		s.store.data.Objects[credentialsPath] = slices.DeleteFunc(s.store.data.Objects[credentialsPath], func(obj *object) bool {
+			return obj.Attributes["database-id"] == id
+		})
+	}
+
+	if err := s.store.saveLocked(); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error saving state", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) createCredentials(w http.ResponseWriter, r *http.Request) {
+	c := collections[credentialsPath]
+
+	attrs := map[string]interface{}{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+	if len(bytes.TrimSpace(body)) > 0 {
+		resources, bulk, ok := decodeTypedResources(w, body, c)
+		if !ok {
+			return
+		}
+		if bulk || len(resources) != 1 {
+			writeError(w, http.StatusBadRequest, "Invalid request", "Credentials requests take a single resource object.")
+			return
+		}
+		if err := c.checkAttributes(resources[0].Attributes, true); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid credentials request", err.Error())
+			return
+		}
+		attrs = resources[0].Attributes
+	}
+
+	ttl := defaultCredentialsTTL
+	if v, ok := attrs["ttl"].(float64); ok {
+		ttl = time.Duration(v) * time.Second
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	databaseID := r.PathValue("id")
+	if db, _ := s.store.findLocked("databases", databaseID); db == nil {
+		writeNotFound(w, collections["databases"], databaseID)
+		return
+	}
+
+	now := time.Now().UTC()
+	s.pruneCredentialsLocked(now)
+
+	obj := &object{
+		ID: newID(c),
+		Attributes: map[string]interface{}{
+			"database-id": databaseID,
+			"username":    "fws_" + randomHex(4),
+			"password":    randomHex(16),
+			"expires-at":  now.Add(ttl).Format("2006-01-02T15:04:05Z"),
+		},
+		CreatedAt: now,
+	}
+	s.store.data.Objects[credentialsPath] = append(s.store.data.Objects[credentialsPath], obj)
+
+	if err := s.store.saveLocked(); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error saving state", err.Error())
+		return
+	}
+
+	writeDocument(w, http.StatusCreated, newEncoder(s.store, &query{}).one(c, obj))
+}
+
+func (s *server) deleteCredentials(w http.ResponseWriter, r *http.Request) {
+	c := collections[credentialsPath]
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	id := r.PathValue("credentials")
+	obj, i := s.store.findLocked(credentialsPath, id)
+	if obj == nil || obj.Attributes["database-id"] != r.PathValue("id") {
+		writeNotFound(w, c, id)
+		return
+	}
+	s.store.data.Objects[credentialsPath] = slices.Delete(s.store.data.Objects[credentialsPath], i, i+1)
+
+	if err := s.store.saveLocked(); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error saving state", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pruneCredentialsLocked forgets expired credentials. s.store.mu must be
+// held.
+func (s *server) pruneCredentialsLocked(now time.Time) {
+	s.store.data.Objects[credentialsPath] = slices.DeleteFunc(s.store.data.Objects[credentialsPath], func(obj *object) bool {
+		expiresAt, err := time.Parse(time.RFC3339, fmt.Sprint(obj.Attributes["expires-at"]))
+		return err == nil && now.After(expiresAt)
+	})
+}
+
+// setAttributes copies attrs onto obj, skipping nulls and write-only
+// attributes.
+func setAttributes(c *collection, obj *object, attrs map[string]interface{}) {
+	for name, v := range attrs {
+		if v == nil || c.attributes[name].writeOnly {
+			continue
+		}
+		obj.Attributes[name] = v
+	}
+}
+
+// readResources reads the resource objects of c from the request body, or
+// writes an error and returns false.
+func readResources(w http.ResponseWriter, r *http.Request, c *collection) ([]*resourceObject, bool, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return nil, false, false
+	}
+	return decodeTypedResources(w, body, c)
+}
+
+func decodeTypedResources(w http.ResponseWriter, body []byte, c *collection) ([]*resourceObject, bool, bool) {
+	resources, bulk, err := decodeResources(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request", err.Error())
+		return nil, false, false
+	}
+
+	for _, res := range resources {
+		if res.Type != c.jsonType {
+			writeError(w, http.StatusConflict, "Conflict", fmt.Sprintf("Expected resources of type %q, got %q.", c.jsonType, res.Type))
+			return nil, false, false
+		}
+		if res.Attributes == nil {
+			res.Attributes = map[string]interface{}{}
+		}
+	}
+
+	return resources, bulk, true
+}
+
+func writeDocument(w http.ResponseWriter, status int, doc interface{}) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		log.Printf("error writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, title, detail string) {
+	writeDocument(w, status, errorsDocument{
+		Errors: []errorObject{{
+			Status: fmt.Sprint(status),
+			Title:  title,
+			Detail: detail,
+		}},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, c *collection, id string) {
+	writeError(w, http.StatusNotFound, "Not found", fmt.Sprintf("No %s with ID %q.", c.jsonType, id))
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
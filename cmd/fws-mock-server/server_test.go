@@ -0,0 +1,344 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// Every request is logged.
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// testAPI is the mock API, seeded with the demo data, served for a test.
+type testAPI struct {
+	t      *testing.T
+	server *server
+	srv    *httptest.Server
+}
+
+func newTestAPI(t *testing.T, rules ...*faultRule) *testAPI {
+	t.Helper()
+
+	s, err := openStore("", demoSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &faults{rules: rules, hidden: map[string]time.Time{}}
+
+	api := &testAPI{t: t, server: newServer(config{}, s, f)}
+	api.srv = httptest.NewTLSServer(api.server.handler())
+	t.Cleanup(api.srv.Close)
+	return api
+}
+
+// testResponse is a decoded response from the mock API.
+type testResponse struct {
+	status int
+	header http.Header
+
+	Data     json.RawMessage   `json:"data"`
+	Included []*resourceObject `json:"included"`
+	Meta     paginationMeta    `json:"meta"`
+	Errors   []errorObject     `json:"errors"`
+}
+
+// one returns the response's primary data as a single resource object.
+func (r *testResponse) one(t *testing.T) *resourceObject {
+	t.Helper()
+	var res resourceObject
+	if err := json.Unmarshal(r.Data, &res); err != nil {
+		t.Fatalf("error decoding %s: %v", r.Data, err)
+	}
+	return &res
+}
+
+// many returns the response's primary data as a list of resource objects.
+func (r *testResponse) many(t *testing.T) []*resourceObject {
+	t.Helper()
+	var res []*resourceObject
+	if err := json.Unmarshal(r.Data, &res); err != nil {
+		t.Fatalf("error decoding %s: %v", r.Data, err)
+	}
+	return res
+}
+
+// do sends a request with a token and, if set, a body, with the headers
+// given as name, value pairs.
+func (a *testAPI) do(method, path, body string, header ...string) *testResponse {
+	a.t.Helper()
+
+	req, err := http.NewRequest(method, a.srv.URL+apiPrefix+path, strings.NewReader(body))
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer test-token")
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	resp, err := a.srv.Client().Do(req)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	r := &testResponse{status: resp.StatusCode, header: resp.Header}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, r); err != nil {
+			a.t.Fatalf("error decoding %s: %v", raw, err)
+		}
+	}
+	return r
+}
+
+// want fails the test unless r has the given status.
+func (r *testResponse) want(t *testing.T, status int) *testResponse {
+	t.Helper()
+	if r.status != status {
+		t.Fatalf("got status %d, want %d: %+v", r.status, status, r.Errors)
+	}
+	return r
+}
+
+func vpcBody(name, cidrBlock string) string {
+	return fmt.Sprintf(`{"data": {"type": "fake-resources-vpcs", "attributes": {"name": %q, "cidr_block": %q}}}`, name, cidrBlock)
+}
+
+func TestServer_crud(t *testing.T) {
+	api := newTestAPI(t)
+
+	created := api.do("POST", "vpcs", vpcBody("main", "10.1.0.0/16")).want(t, http.StatusCreated).one(t)
+	if created.Type != "fake-resources-vpcs" || !strings.HasPrefix(created.ID, "vpc-") {
+		t.Fatalf("got %+v", created)
+	}
+
+	read := api.do("GET", "vpcs/"+created.ID, "").want(t, http.StatusOK).one(t)
+	if read.Attributes["name"] != "main" || read.Attributes["cidr_block"] != "10.1.0.0/16" {
+		t.Errorf("got attributes %v", read.Attributes)
+	}
+
+	update := `{"data": {"type": "fake-resources-vpcs", "id": "` + created.ID + `", "attributes": {"name": "renamed"}}}`
+	updated := api.do("PATCH", "vpcs/"+created.ID, update).want(t, http.StatusOK).one(t)
+	if updated.Attributes["name"] != "renamed" || updated.Attributes["cidr_block"] != "10.1.0.0/16" {
+		t.Errorf("got attributes %v after update", updated.Attributes)
+	}
+
+	var ids []string
+	for _, res := range api.do("GET", "vpcs", "").want(t, http.StatusOK).many(t) {
+		ids = append(ids, res.ID)
+	}
+	if !slices.Contains(ids, created.ID) {
+		t.Errorf("list %v does not hold %s", ids, created.ID)
+	}
+
+	api.do("DELETE", "vpcs/"+created.ID, "").want(t, http.StatusNoContent)
+	api.do("GET", "vpcs/"+created.ID, "").want(t, http.StatusNotFound)
+	api.do("DELETE", "vpcs/"+created.ID, "").want(t, http.StatusNotFound)
+}
+
+func TestServer_invalidRequests(t *testing.T) {
+	api := newTestAPI(t)
+
+	cases := map[string]struct {
+		method, path, body string
+		want               int
+	}{
+		"unknown collection": {"GET", "widgets", "", http.StatusNotFound},
+		"missing attribute":  {"POST", "vpcs", `{"data": {"type": "fake-resources-vpcs", "attributes": {"name": "x"}}}`, http.StatusUnprocessableEntity},
+		"invalid CIDR":       {"POST", "vpcs", vpcBody("x", "not a cidr"), http.StatusUnprocessableEntity},
+		"unknown attribute":  {"POST", "vpcs", `{"data": {"type": "fake-resources-vpcs", "attributes": {"name": "x", "cidr_block": "10.0.0.0/8", "color": "red"}}}`, http.StatusUnprocessableEntity},
+		"invalid tags":       {"POST", "databases", `{"data": {"type": "fake-resources-databases", "attributes": {"name": "x", "size": 1, "tags": {"env": 1}}}}`, http.StatusUnprocessableEntity},
+		"wrong type":         {"POST", "vpcs", `{"data": {"type": "fake-resources-servers", "attributes": {}}}`, http.StatusConflict},
+		"invalid JSON":       {"POST", "vpcs", `{`, http.StatusBadRequest},
+		"mismatched ID":      {"PATCH", "vpcs/vpc-demo", `{"data": {"type": "fake-resources-vpcs", "id": "vpc-other", "attributes": {}}}`, http.StatusConflict},
+		"bad page number":    {"GET", "vpcs?page[number]=0", "", http.StatusBadRequest},
+		"unknown parameter":  {"GET", "vpcs?color=red", "", http.StatusBadRequest},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := api.do(tc.method, tc.path, tc.body).want(t, tc.want)
+			if len(resp.Errors) == 0 {
+				t.Error("no JSON:API errors in the response")
+			}
+		})
+	}
+}
+
+func TestServer_authentication(t *testing.T) {
+	api := newTestAPI(t)
+	api.server.config.token = "the-token"
+
+	api.do("GET", "vpcs", "").want(t, http.StatusUnauthorized)
+	api.do("GET", "vpcs", "", "Authorization", "Bearer the-token").want(t, http.StatusOK)
+	api.do("GET", "vpcs", "", "Authorization", "").want(t, http.StatusUnauthorized)
+}
+
+func TestServer_bulkCreate(t *testing.T) {
+	api := newTestAPI(t)
+
+	body := `{"data": [
+		{"type": "fake-resources-vpcs", "attributes": {"name": "a", "cidr_block": "10.1.0.0/16"}},
+		{"type": "fake-resources-vpcs", "attributes": {"name": "b", "cidr_block": "10.2.0.0/16"}}
+	]}`
+	created := api.do("POST", "vpcs", body).want(t, http.StatusCreated).many(t)
+	if len(created) != 2 || created[0].Attributes["name"] != "a" || created[1].Attributes["name"] != "b" {
+		t.Fatalf("got %+v", created)
+	}
+
+	// A bulk create with an invalid object creates none of them.
+	body = `{"data": [
+		{"type": "fake-resources-vpcs", "attributes": {"name": "c", "cidr_block": "10.3.0.0/16"}},
+		{"type": "fake-resources-vpcs", "attributes": {"name": "d"}}
+	]}`
+	api.do("POST", "vpcs", body).want(t, http.StatusUnprocessableEntity)
+	if got := api.do("GET", "vpcs?filter[name]=c", "").many(t); len(got) != 0 {
+		t.Errorf("got %d VPCs named c, want 0", len(got))
+	}
+}
+
+func TestServer_paging(t *testing.T) {
+	api := newTestAPI(t)
+	for i := range 5 {
+		api.do("POST", "vpcs", vpcBody(fmt.Sprintf("vpc-%d", i), "10.0.0.0/16")).want(t, http.StatusCreated)
+	}
+
+	// The demo VPC and five more.
+	resp := api.do("GET", "vpcs?page[size]=2&page[number]=2", "").want(t, http.StatusOK)
+	if got := len(resp.many(t)); got != 2 {
+		t.Errorf("got %d VPCs, want 2", got)
+	}
+	want := pagination{CurrentPage: 2, PreviousPage: 1, NextPage: 3, TotalPages: 3, TotalCount: 6}
+	if resp.Meta.Pagination != want {
+		t.Errorf("got pagination %+v, want %+v", resp.Meta.Pagination, want)
+	}
+
+	last := api.do("GET", "vpcs?page[size]=2&page[number]=3", "").want(t, http.StatusOK)
+	if last.Meta.Pagination.NextPage != 0 {
+		t.Errorf("got next page %d on the last page", last.Meta.Pagination.NextPage)
+	}
+
+	past := api.do("GET", "vpcs?page[size]=2&page[number]=9", "").want(t, http.StatusOK)
+	if got := len(past.many(t)); got != 0 {
+		t.Errorf("got %d VPCs past the last page", got)
+	}
+}
+
+func TestServer_filterSortFields(t *testing.T) {
+	api := newTestAPI(t)
+	for _, name := range []string{"b", "c", "a"} {
+		api.do("POST", "vpcs", vpcBody(name, "10.9.0.0/16")).want(t, http.StatusCreated)
+	}
+
+	var names []string
+	for _, res := range api.do("GET", "vpcs?filter[cidr_block]=10.9.0.0/16&sort=-name", "").want(t, http.StatusOK).many(t) {
+		names = append(names, res.Attributes["name"].(string))
+	}
+	if !slices.Equal(names, []string{"c", "b", "a"}) {
+		t.Errorf("got %v, want [c b a]", names)
+	}
+
+	// List attributes match if any element does.
+	lbs := api.do("GET", "load_balancers?filter[servers]=Demo%20Server%202", "").want(t, http.StatusOK).many(t)
+	if len(lbs) != 1 || lbs[0].ID != "lb-demo" {
+		t.Errorf("got %+v", lbs)
+	}
+
+	sparse := api.do("GET", "vpcs/vpc-demo?fields[fake-resources-vpcs]=name", "").want(t, http.StatusOK).one(t)
+	if _, ok := sparse.Attributes["cidr_block"]; ok || sparse.Attributes["name"] != "Demo VPC" {
+		t.Errorf("got attributes %v, want only name", sparse.Attributes)
+	}
+}
+
+func TestServer_include(t *testing.T) {
+	api := newTestAPI(t)
+
+	server := api.do("GET", "servers/srv-demo-1?include=parent-vpc", "").want(t, http.StatusOK)
+	rel := server.one(t).Relationships["parent-vpc"].Data.(map[string]interface{})
+	if rel["id"] != "vpc-demo" || rel["type"] != "fake-resources-vpcs" {
+		t.Errorf("got relationship %v", rel)
+	}
+	if len(server.Included) != 1 || server.Included[0].ID != "vpc-demo" {
+		t.Errorf("got included %+v", server.Included)
+	}
+
+	// Nested includes are followed, and each object is included once.
+	lb := api.do("GET", "load_balancers/lb-demo?include=attached-servers.parent-vpc", "").want(t, http.StatusOK)
+	var included []string
+	for _, res := range lb.Included {
+		included = append(included, res.ID)
+	}
+	slices.Sort(included)
+	if !slices.Equal(included, []string{"srv-demo-1", "srv-demo-2", "vpc-demo"}) {
+		t.Errorf("got included %v", included)
+	}
+	if servers := lb.one(t).Relationships["attached-servers"].Data.([]interface{}); len(servers) != 2 {
+		t.Errorf("got attached servers %v", servers)
+	}
+
+	// Without an include, there are no relationships.
+	plain := api.do("GET", "servers/srv-demo-1", "").want(t, http.StatusOK)
+	if plain.one(t).Relationships != nil || plain.Included != nil {
+		t.Errorf("got %s and included %+v without an include", plain.Data, plain.Included)
+	}
+}
+
+func TestServer_idempotency(t *testing.T) {
+	api := newTestAPI(t)
+
+	first := api.do("POST", "vpcs", vpcBody("once", "10.0.0.0/16"), "Idempotency-Key", "key-1").want(t, http.StatusCreated)
+	retry := api.do("POST", "vpcs", vpcBody("once", "10.0.0.0/16"), "Idempotency-Key", "key-1").want(t, http.StatusCreated)
+	if retry.one(t).ID != first.one(t).ID {
+		t.Errorf("retry created %s, want the original %s", retry.one(t).ID, first.one(t).ID)
+	}
+	if retry.header.Get("Idempotent-Replayed") != "true" {
+		t.Error("retry was not marked as replayed")
+	}
+	if got := api.do("GET", "vpcs?filter[name]=once", "").many(t); len(got) != 1 {
+		t.Errorf("got %d VPCs, want 1", len(got))
+	}
+
+	// Reusing the key for a different request is an error.
+	api.do("POST", "vpcs", vpcBody("other", "10.0.0.0/16"), "Idempotency-Key", "key-1").want(t, http.StatusUnprocessableEntity)
+
+	// Failed creates are not replayed.
+	api.do("POST", "vpcs", vpcBody("", "10.0.0.0/16"), "Idempotency-Key", "key-2").want(t, http.StatusUnprocessableEntity)
+	api.do("POST", "vpcs", vpcBody("fixed", "10.0.0.0/16"), "Idempotency-Key", "key-2").want(t, http.StatusCreated)
+}
+
+func TestServer_databaseCredentials(t *testing.T) {
+	api := newTestAPI(t)
+
+	body := `{"data": {"type": "fake-resources-databases", "attributes": {"name": "db", "size": 8, "password": "hunter2"}}}`
+	db := api.do("POST", "databases", body).want(t, http.StatusCreated).one(t)
+	if _, ok := db.Attributes["password"]; ok {
+		t.Error("the write-only password was returned")
+	}
+
+	creds := api.do("POST", "databases/"+db.ID+"/credentials", "").want(t, http.StatusCreated).one(t)
+	if creds.Attributes["username"] == "" || creds.Attributes["password"] == "" || creds.Attributes["database-id"] != db.ID {
+		t.Errorf("got credentials %v", creds.Attributes)
+	}
+
+	api.do("DELETE", "databases/"+db.ID+"/credentials/"+creds.ID, "").want(t, http.StatusNoContent)
+	api.do("DELETE", "databases/"+db.ID+"/credentials/"+creds.ID, "").want(t, http.StatusNotFound)
+	api.do("POST", "databases/db-missing/credentials", "").want(t, http.StatusNotFound)
+}
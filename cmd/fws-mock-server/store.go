@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//go:embed seed.json
+var demoSeed []byte
+
+// object is a stored API object: a JSON:API resource without its type,
+// which is given by the collection it is stored in.
+type object struct {
+	ID         string                 `json:"id"`
+	Attributes map[string]interface{} `json:"attributes"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// storeData is everything the mock API knows, as persisted to disk.
+type storeData struct {
+	// Objects holds each collection's objects, keyed by collection path,
+	// in creation order.
+	Objects map[string][]*object `json:"objects"`
+}
+
+// store holds the mock API's state, optionally persisted to a JSON file
+// after every change.
+type store struct {
+	path string
+
+	mu   sync.Mutex
+	data storeData
+}
+
+// openStore loads the store from path. If path does not exist yet it
+// starts from seed, which may be empty. An empty path keeps the store in
+// memory only.
+func openStore(path string, seed []byte) (*store, error) {
+	s := &store{path: path}
+
+	raw := seed
+	if path != "" {
+		b, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("error reading state: %w", err)
+		default:
+			raw = b
+		}
+	}
+
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &s.data); err != nil {
+			return nil, fmt.Errorf("error decoding state: %w", err)
+		}
+	}
+	if s.data.Objects == nil {
+		s.data.Objects = map[string][]*object{}
+	}
+
+	// Seeded objects may leave out the parts only the server fills in.
+	now := time.Now().UTC()
+	for path, objs := range s.data.Objects {
+		c, ok := collections[path]
+		if !ok {
+			return nil, fmt.Errorf("unknown collection %q in state", path)
+		}
+		for _, obj := range objs {
+			if obj.ID == "" {
+				obj.ID = newID(c)
+			}
+			if obj.Attributes == nil {
+				obj.Attributes = map[string]interface{}{}
+			}
+			if obj.CreatedAt.IsZero() {
+				obj.CreatedAt = now
+			}
+		}
+	}
+
+	return s, s.saveLocked()
+}
+
+// loadSeed returns the seed named by the -seed flag: nothing, the built-in
+// demo data, or the contents of a file.
+func loadSeed(name string) ([]byte, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "demo":
+		return demoSeed, nil
+	default:
+		return os.ReadFile(name)
+	}
+}
+
+// saveLocked writes the store to disk. s.mu must be held, except while
+// the store is being opened.
+func (s *store) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a partial
+	// state file behind.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".fws-mock-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// findLocked returns the object with id in the collection at path, and its
+// index. s.mu must be held.
+func (s *store) findLocked(path, id string) (*object, int) {
+	for i, obj := range s.data.Objects[path] {
+		if obj.ID == id {
+			return obj, i
+		}
+	}
+	return nil, -1
+}
+
+// newID returns a new random ID for an object in c.
+func newID(c *collection) string {
+	return c.idPrefix + "-" + randomHex(8)
+}
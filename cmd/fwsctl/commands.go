@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+func runList(ctx context.Context, cli *cli, args []string) error {
+	fs := cli.flags(true)
+	args, err := cli.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return cli.usageError(fs, "expected a type")
+	}
+
+	rt, err := lookupType(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	records := []record{}
+	for r, err := range rt.list(ctx, c) {
+		if err != nil {
+			return fmt.Errorf("error listing %s: %w", rt.name, err)
+		}
+		records = append(records, r)
+	}
+
+	return cli.print(rt, records, false)
+}
+
+func runGet(ctx context.Context, cli *cli, args []string) error {
+	fs := cli.flags(true)
+	include := fs.Bool("include", false, "also show the objects each object references: a server's VPC, or a load balancer's servers")
+	args, err := cli.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return cli.usageError(fs, "expected a type and at least one ID")
+	}
+
+	rt, err := lookupType(args[0])
+	if err != nil {
+		return err
+	}
+	if *include && rt.include == "" {
+		return cli.usageError(fs, "%s have no related objects to include", rt.name)
+	}
+	c, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	var records []record
+	for _, id := range args[1:] {
+		r, err := rt.get(ctx, c, id, *include)
+		if errors.Is(err, client.ErrResourceNotFound) {
+			return fmt.Errorf("%s %s not found", rt.name, id)
+		}
+		if err != nil {
+			return fmt.Errorf("error reading %s %s: %w", rt.name, id, err)
+		}
+		records = append(records, r)
+	}
+
+	if err := cli.print(rt, records, len(records) == 1); err != nil {
+		return err
+	}
+	if !*include || cli.output != "table" {
+		return nil
+	}
+
+	// Tables show the included objects in a table of their own.
+	var included []record
+	for _, r := range records {
+		if objs, ok := r["included"].([]record); ok {
+			included = append(included, objs...)
+		}
+	}
+	if len(included) == 0 {
+		return nil
+	}
+	includedType, err := lookupType(rt.included)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cli.stdout)
+	return cli.printTable(includedType.columns, included)
+}
+
+func runCreate(ctx context.Context, cli *cli, args []string) error {
+	fs := cli.flags(true)
+	args, err := cli.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return cli.usageError(fs, "expected a type")
+	}
+
+	rt, err := lookupType(args[0])
+	if err != nil {
+		return err
+	}
+	a, err := parseAttributes(args[1:])
+	if err != nil {
+		return cli.usageError(fs, "%v", err)
+	}
+	c, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	r, err := rt.create(ctx, c, a)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", rt.name, err)
+	}
+
+	return cli.print(rt, []record{r}, true)
+}
+
+func runDelete(ctx context.Context, cli *cli, args []string) error {
+	fs := cli.flags(false)
+	args, err := cli.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return cli.usageError(fs, "expected a type and at least one ID")
+	}
+
+	rt, err := lookupType(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, id := range args[1:] {
+		err := deleteObject(ctx, c, rt, id)
+		if errors.Is(err, client.ErrResourceNotFound) {
+			errs = append(errs, fmt.Errorf("%s %s not found", rt.name, id))
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error deleting %s %s: %w", rt.name, id, err))
+			continue
+		}
+		fmt.Fprintf(cli.stdout, "Deleted %s %s\n", rt.name, id)
+	}
+
+	return errors.Join(errs...)
+}
+
+// deleteObject deletes the object of type rt with the given ID.
+func deleteObject(ctx context.Context, c *client.Client, rt *resourceType, id string) error {
+	req, err := c.NewRequest("DELETE", fmt.Sprintf("%s/%s", rt.name, id), nil)
+	if err != nil {
+		return err
+	}
+	return c.Do(ctx, req, nil)
+}
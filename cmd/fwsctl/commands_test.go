@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serverWithVpc is a server read with its VPC included.
+const serverWithVpc = `{
+  "data": {
+    "type": "fake-resources-servers",
+    "id": "srv-1",
+    "attributes": {"name": "web", "server-type": "t2.micro", "vpc": "main"},
+    "relationships": {
+      "parent-vpc": {"data": {"type": "fake-resources-vpcs", "id": "vpc-1"}}
+    }
+  },
+  "included": [
+    {
+      "type": "fake-resources-vpcs",
+      "id": "vpc-1",
+      "attributes": {"name": "main", "cidr_block": "10.0.0.0/16"}
+    }
+  ]
+}`
+
+func TestGet_include(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/fake-resources/servers/srv-1" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("include"); got != "parent-vpc" {
+			t.Errorf("got include %q, want %q", got, "parent-vpc")
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(serverWithVpc))
+	}))
+	defer srv.Close()
+
+	get := func(output string) string {
+		t.Helper()
+
+		var stdout, stderr bytes.Buffer
+		args := []string{
+			"get", "servers", "srv-1", "-include", "-o", output,
+			"-hostname", strings.TrimPrefix(srv.URL, "https://"),
+			"-token", "test-token",
+			"-insecure-skip-verify",
+		}
+		if code := run(context.Background(), args, &stdout, &stderr); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr.String())
+		}
+		return stdout.String()
+	}
+
+	var got struct {
+		ID       string              `json:"id"`
+		Included []map[string]string `json:"included"`
+	}
+	if err := json.Unmarshal([]byte(get("json")), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "srv-1" {
+		t.Errorf("got ID %q, want %q", got.ID, "srv-1")
+	}
+	want := map[string]string{"id": "vpc-1", "name": "main", "cidr_block": "10.0.0.0/16"}
+	if len(got.Included) != 1 || !maps.Equal(got.Included[0], want) {
+		t.Errorf("got included %v, want [%v]", got.Included, want)
+	}
+
+	// Tables show the VPC in a table of its own.
+	if table := get("table"); !strings.Contains(table, "CIDR_BLOCK") || !strings.Contains(table, "10.0.0.0/16") {
+		t.Errorf("table does not show the included VPC:\n%s", table)
+	}
+}
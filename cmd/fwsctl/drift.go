@@ -0,0 +1,272 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+	"gopkg.in/yaml.v3"
+)
+
+func runDrift(ctx context.Context, cli *cli, args []string) error {
+	fs := cli.flags(true)
+	statePath := fs.String("state", "terraform.tfstate", `the Terraform state file to check, or "-" to read it from stdin, such as from terraform state pull`)
+	args, err := cli.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return cli.usageError(fs, "unexpected arguments")
+	}
+
+	instances, err := readState(*statePath)
+	if err != nil {
+		return err
+	}
+	c, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	drifts := []drift{}
+	drifted := 0
+	var errs []error
+	for _, inst := range instances {
+		found, err := checkDrift(ctx, c, inst)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(found) > 0 {
+			drifted++
+		}
+		drifts = append(drifts, found...)
+	}
+
+	if err := cli.printDrift(drifts); err != nil {
+		return err
+	}
+
+	if drifted > 0 {
+		errs = append(errs, fmt.Errorf("found drift in %d of %d objects", drifted, len(instances)))
+	} else if len(errs) == 0 && cli.output == "table" {
+		fmt.Fprintf(cli.stdout, "No drift found in %d objects.\n", len(instances))
+	}
+
+	return errors.Join(errs...)
+}
+
+// drift is a difference between an object in the state and in the API.
+type drift struct {
+	Address string `json:"address" yaml:"address"`
+	ID      string `json:"id" yaml:"id"`
+
+	// Missing is set when the object no longer exists.
+	Missing bool `json:"missing,omitempty" yaml:"missing,omitempty"`
+
+	// Attribute is the attribute that differs, with its value in the
+	// state and in the API.
+	Attribute string      `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	State     interface{} `json:"state" yaml:"state"`
+	API       interface{} `json:"api" yaml:"api"`
+}
+
+// stateInstance is a fakewebservices resource instance in the state.
+type stateInstance struct {
+	rt         *resourceType
+	address    string
+	attributes map[string]interface{}
+}
+
+// readState returns the fakewebservices resource instances in a state
+// file.
+func readState(path string) ([]*stateInstance, error) {
+	var raw []byte
+	var err error
+	if path == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading state: %w", err)
+	}
+
+	var state struct {
+		Version   int `json:"version"`
+		Resources []struct {
+			Module    string `json:"module"`
+			Mode      string `json:"mode"`
+			Type      string `json:"type"`
+			Name      string `json:"name"`
+			Instances []struct {
+				IndexKey   interface{}            `json:"index_key"`
+				Attributes map[string]interface{} `json:"attributes"`
+			} `json:"instances"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("error decoding state: %w", err)
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported state version %d; only version 4, written by Terraform 0.12 and later, is supported", state.Version)
+	}
+
+	var instances []*stateInstance
+	for _, res := range state.Resources {
+		if res.Mode != "managed" {
+			continue
+		}
+		rt := lookupTerraformType(res.Type)
+		if rt == nil {
+			continue
+		}
+
+		address := res.Type + "." + res.Name
+		if res.Module != "" {
+			address = res.Module + "." + address
+		}
+
+		for _, inst := range res.Instances {
+			instAddress := address
+			switch key := inst.IndexKey.(type) {
+			case float64:
+				instAddress += fmt.Sprintf("[%d]", int(key))
+			case string:
+				instAddress += fmt.Sprintf("[%q]", key)
+			}
+
+			instances = append(instances, &stateInstance{
+				rt:         rt,
+				address:    instAddress,
+				attributes: inst.Attributes,
+			})
+		}
+	}
+
+	return instances, nil
+}
+
+// checkDrift reads inst's object and returns how it differs from the
+// state.
+func checkDrift(ctx context.Context, c *client.Client, inst *stateInstance) ([]drift, error) {
+	id, _ := inst.attributes["id"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("%s has no ID in the state", inst.address)
+	}
+
+	r, err := inst.rt.get(ctx, c, id, false)
+	if errors.Is(err, client.ErrResourceNotFound) {
+		return []drift{{Address: inst.address, ID: id, Missing: true}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s (%s): %w", inst.address, id, err)
+	}
+
+	var drifts []drift
+	for _, attr := range inst.rt.columns {
+		if attr == "id" {
+			continue
+		}
+
+		stateValue := normalize(inst.attributes[attr])
+		apiValue := normalize(r[attr])
+		if !reflect.DeepEqual(stateValue, apiValue) {
+			drifts = append(drifts, drift{
+				Address:   inst.address,
+				ID:        id,
+				Attribute: attr,
+				State:     stateValue,
+				API:       apiValue,
+			})
+		}
+	}
+
+	return drifts, nil
+}
+
+// normalize converts an attribute value from the state or the API to a
+// common form for comparison. Empty values are nil, numbers are int64s,
+// and lists are sorted, as the only list attribute is a set.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return v
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	case []string:
+		if len(v) == 0 {
+			return nil
+		}
+		return slices.Sorted(slices.Values(v))
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return normalize(items)
+	}
+	return v
+}
+
+// printDrift writes drifts in the output format.
+func (cli *cli) printDrift(drifts []drift) error {
+	switch cli.output {
+	case "json":
+		enc := json.NewEncoder(cli.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(drifts)
+
+	case "yaml":
+		enc := yaml.NewEncoder(cli.stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(drifts); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	if len(drifts) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cli.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tID\tDRIFT")
+	for _, d := range drifts {
+		desc := "missing"
+		if !d.Missing {
+			desc = fmt.Sprintf("%s: %s in state, %s in API", d.Attribute, formatDriftValue(d.State), formatDriftValue(d.API))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", d.Address, d.ID, desc)
+	}
+	return w.Flush()
+}
+
+func formatDriftValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "unset"
+	case []string:
+		return "[" + strings.Join(v, ", ") + "]"
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
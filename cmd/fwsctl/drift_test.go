@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// driftState has an up-to-date VPC, a server whose type has changed, a
+// load balancer in a module whose name has changed and whose servers are
+// in a different order, and a database that has been deleted, along with
+// resources drift ignores.
+const driftState = `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed", "type": "fakewebservices_vpc", "name": "main",
+      "instances": [{"attributes": {"id": "vpc-1", "name": "main", "cidr_block": "10.0.0.0/16"}}]
+    },
+    {
+      "mode": "managed", "type": "fakewebservices_server", "name": "web",
+      "instances": [{"index_key": 0, "attributes": {"id": "srv-1", "name": "web", "type": "t2.micro", "vpc": "main", "tags": null}}]
+    },
+    {
+      "module": "module.lb", "mode": "managed", "type": "fakewebservices_load_balancer", "name": "this",
+      "instances": [{"index_key": "a", "attributes": {"id": "lb-1", "name": "lb", "servers": ["web", "api"]}}]
+    },
+    {
+      "mode": "managed", "type": "fakewebservices_database", "name": "db",
+      "instances": [{"attributes": {"id": "db-1", "name": "db", "size": 10, "password": null}}]
+    },
+    {
+      "mode": "data", "type": "fakewebservices_server_types", "name": "all",
+      "instances": [{"attributes": {}}]
+    },
+    {
+      "mode": "managed", "type": "null_resource", "name": "other",
+      "instances": [{"attributes": {"id": "123"}}]
+    }
+  ]
+}`
+
+// driftAPI serves the objects of driftState as they are now.
+func driftAPI(t *testing.T) *httptest.Server {
+	objects := map[string]string{
+		"vpcs/vpc-1": `{"type": "fake-resources-vpcs", "id": "vpc-1",
+			"attributes": {"name": "main", "cidr_block": "10.0.0.0/16"}}`,
+		"servers/srv-1": `{"type": "fake-resources-servers", "id": "srv-1",
+			"attributes": {"name": "web", "server-type": "t2.large", "vpc": "main"}}`,
+		"load_balancers/lb-1": `{"type": "fake-resources-load-balancers", "id": "lb-1",
+			"attributes": {"name": "lb-renamed", "servers": ["api", "web"]}}`,
+	}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		obj, ok := objects[strings.TrimPrefix(r.URL.Path, "/api/fake-resources/")]
+		if r.Method != http.MethodGet || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": ` + obj + `}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// runDriftCommand runs fwsctl drift against srv for a state file holding
+// state.
+func runDriftCommand(t *testing.T, srv *httptest.Server, state string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+
+	statePath := filepath.Join(t.TempDir(), "terraform.tfstate")
+	if err := os.WriteFile(statePath, []byte(state), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	args = append([]string{
+		"drift", "-state", statePath,
+		"-hostname", strings.TrimPrefix(srv.URL, "https://"),
+		"-token", "test-token",
+		"-insecure-skip-verify",
+	}, args...)
+
+	var out, errOut bytes.Buffer
+	code = run(context.Background(), args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestDrift(t *testing.T) {
+	code, stdout, stderr := runDriftCommand(t, driftAPI(t), driftState, "-o", "json")
+	if code != 1 {
+		t.Fatalf("got exit code %d, want 1: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "found drift in 3 of 4 objects") {
+		t.Errorf("got stderr %q", stderr)
+	}
+
+	var got []drift
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []drift{
+		{Address: "fakewebservices_server.web[0]", ID: "srv-1", Attribute: "type", State: "t2.micro", API: "t2.large"},
+		{Address: `module.lb.fakewebservices_load_balancer.this["a"]`, ID: "lb-1", Attribute: "name", State: "lb", API: "lb-renamed"},
+		{Address: "fakewebservices_database.db", ID: "db-1", Missing: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDrift_table(t *testing.T) {
+	code, stdout, _ := runDriftCommand(t, driftAPI(t), driftState)
+	if code != 1 {
+		t.Fatalf("got exit code %d, want 1", code)
+	}
+
+	want := `ADDRESS                                            ID     DRIFT
+fakewebservices_server.web[0]                      srv-1  type: "t2.micro" in state, "t2.large" in API
+module.lb.fakewebservices_load_balancer.this["a"]  lb-1   name: "lb" in state, "lb-renamed" in API
+fakewebservices_database.db                        db-1   missing
+`
+	if stdout != want {
+		t.Errorf("got:\n%s\nwant:\n%s", stdout, want)
+	}
+}
+
+func TestDrift_none(t *testing.T) {
+	state := `{"version": 4, "resources": [{
+		"mode": "managed", "type": "fakewebservices_vpc", "name": "main",
+		"instances": [{"attributes": {"id": "vpc-1", "name": "main", "cidr_block": "10.0.0.0/16"}}]
+	}]}`
+
+	code, stdout, stderr := runDriftCommand(t, driftAPI(t), state)
+	if code != 0 {
+		t.Fatalf("got exit code %d: %s", code, stderr)
+	}
+	if stdout != "No drift found in 1 objects.\n" {
+		t.Errorf("got %q", stdout)
+	}
+}
+
+func TestDrift_unsupportedStateVersion(t *testing.T) {
+	code, _, stderr := runDriftCommand(t, driftAPI(t), `{"version": 3, "modules": []}`)
+	if code != 1 || !strings.Contains(stderr, "unsupported state version 3") {
+		t.Errorf("got exit code %d: %s", code, stderr)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]struct {
+		v    interface{}
+		want interface{}
+	}{
+		"empty string":     {"", nil},
+		"string":           {"web", "web"},
+		"state number":     {float64(10), int64(10)},
+		"API number":       {10, int64(10)},
+		"empty API list":   {[]string{}, nil},
+		"API list":         {[]string{"web", "api"}, []string{"api", "web"}},
+		"state list":       {[]interface{}{"web", "api"}, []string{"api", "web"}},
+		"empty state list": {[]interface{}{}, nil},
+		"null":             {nil, nil},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := normalize(tc.v); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
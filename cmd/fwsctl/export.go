@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func runExport(ctx context.Context, cli *cli, args []string) error {
+	fs := cli.flags(false)
+	noImport := fs.Bool("no-import", false, "leave out the import blocks")
+	args, err := cli.parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	types, err := lookupTypes(args)
+	if err != nil {
+		return err
+	}
+	c, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	e := newExporter()
+	for _, rt := range types {
+		for r, err := range rt.list(ctx, c) {
+			if err != nil {
+				return fmt.Errorf("error listing %s: %w", rt.name, err)
+			}
+			e.add(rt, r)
+		}
+	}
+
+	_, err = cli.stdout.Write(e.file(!*noImport).Bytes())
+	return err
+}
+
+// exported is an object being exported.
+type exported struct {
+	rt     *resourceType
+	record record
+	label  string
+}
+
+// exporter generates Terraform configuration for existing objects.
+type exporter struct {
+	objects []*exported
+
+	// labels holds the labels in use for each type.
+	labels map[*resourceType]map[string]bool
+
+	// byName holds the objects of each type by name, for references.
+	// Names shared by several objects map to nil, as a reference to them
+	// would be ambiguous.
+	byName map[string]map[string]*exported
+}
+
+func newExporter() *exporter {
+	return &exporter{
+		labels: map[*resourceType]map[string]bool{},
+		byName: map[string]map[string]*exported{},
+	}
+}
+
+// add adds an object. Objects must be added after the objects they
+// reference.
+func (e *exporter) add(rt *resourceType, r record) {
+	obj := &exported{rt: rt, record: r, label: e.label(rt, r)}
+	e.objects = append(e.objects, obj)
+
+	name, _ := r["name"].(string)
+	if e.byName[rt.name] == nil {
+		e.byName[rt.name] = map[string]*exported{}
+	}
+	if _, ok := e.byName[rt.name][name]; ok {
+		e.byName[rt.name][name] = nil
+	} else {
+		e.byName[rt.name][name] = obj
+	}
+}
+
+var nonIdentifier = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// label returns a unique Terraform resource name for an object, derived
+// from its name.
+func (e *exporter) label(rt *resourceType, r record) string {
+	name, _ := r["name"].(string)
+	base := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	switch {
+	case base == "":
+		base = strings.TrimPrefix(rt.terraformType, "fakewebservices_")
+	case base[0] >= '0' && base[0] <= '9':
+		base = "_" + base
+	}
+
+	if e.labels[rt] == nil {
+		e.labels[rt] = map[string]bool{}
+	}
+	label := base
+	for i := 2; e.labels[rt][label]; i++ {
+		label = fmt.Sprintf("%s_%d", base, i)
+	}
+	e.labels[rt][label] = true
+
+	return label
+}
+
+// file returns the configuration for the objects, each followed by an
+// import block if withImports is set.
+func (e *exporter) file(withImports bool) *hclwrite.File {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	for i, obj := range e.objects {
+		if i > 0 {
+			body.AppendNewline()
+		}
+
+		block := body.AppendNewBlock("resource", []string{obj.rt.terraformType, obj.label}).Body()
+		for _, column := range obj.rt.columns {
+			if column == "id" {
+				continue
+			}
+			if tokens := e.value(obj.rt, column, obj.record[column]); tokens != nil {
+				block.SetAttributeRaw(column, tokens)
+			}
+		}
+
+		if withImports {
+			body.AppendNewline()
+			block := body.AppendNewBlock("import", nil).Body()
+			block.SetAttributeTraversal("to", hcl.Traversal{
+				hcl.TraverseRoot{Name: obj.rt.terraformType},
+				hcl.TraverseAttr{Name: obj.label},
+			})
+			block.SetAttributeValue("id", cty.StringVal(obj.record["id"].(string)))
+		}
+	}
+
+	return f
+}
+
+// value returns the expression for an attribute, referring to the named
+// objects where it can. It returns nil for unset attributes.
+func (e *exporter) value(rt *resourceType, attr string, v interface{}) hclwrite.Tokens {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return e.reference(rt, attr, v)
+
+	case int:
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(v)))
+
+	case []string:
+		if len(v) == 0 {
+			return nil
+		}
+		elems := make([]hclwrite.Tokens, len(v))
+		for i, name := range v {
+			elems[i] = e.reference(rt, attr, name)
+		}
+		return hclwrite.TokensForTuple(elems)
+	}
+
+	panic(fmt.Sprintf("unexpected %T value for %s", v, attr))
+}
+
+// reference returns a reference to the name of the object named name, if
+// attr refers to objects and one was exported, or else the name itself.
+func (e *exporter) reference(rt *resourceType, attr, name string) hclwrite.Tokens {
+	if target := e.byName[rt.references[attr]][name]; target != nil {
+		return hclwrite.TokensForTraversal(hcl.Traversal{
+			hcl.TraverseRoot{Name: target.rt.terraformType},
+			hcl.TraverseAttr{Name: target.label},
+			hcl.TraverseAttr{Name: "name"},
+		})
+	}
+	return hclwrite.TokensForValue(cty.StringVal(name))
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExporter_file(t *testing.T) {
+	e := newExporter()
+	add := func(typeName string, r record) {
+		rt, err := lookupType(typeName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.add(rt, r)
+	}
+
+	add("vpcs", record{"id": "vpc-1", "name": "Main VPC", "cidr_block": "10.0.0.0/16"})
+	add("vpcs", record{"id": "vpc-2", "name": "dup", "cidr_block": "10.1.0.0/16"})
+	add("vpcs", record{"id": "vpc-3", "name": "dup", "cidr_block": "10.2.0.0/16"})
+	add("servers", record{"id": "srv-1", "name": "Web 1", "type": "t2.micro", "vpc": "Main VPC"})
+	add("servers", record{"id": "srv-2", "name": "web-1", "type": "t2.micro", "vpc": "dup"})
+	add("servers", record{"id": "srv-3", "name": "2nd", "type": "t2.small", "vpc": ""})
+	add("load_balancers", record{"id": "lb-1", "name": "", "servers": []string{"Web 1", "2nd", "missing"}})
+	add("databases", record{"id": "db-1", "name": "db", "size": 10})
+
+	// Labels are unique identifiers; references are only made to objects
+	// with unique names, and import blocks follow each resource.
+	want := `resource "fakewebservices_vpc" "main_vpc" {
+  name       = "Main VPC"
+  cidr_block = "10.0.0.0/16"
+}
+
+import {
+  to = fakewebservices_vpc.main_vpc
+  id = "vpc-1"
+}
+
+resource "fakewebservices_vpc" "dup" {
+  name       = "dup"
+  cidr_block = "10.1.0.0/16"
+}
+
+import {
+  to = fakewebservices_vpc.dup
+  id = "vpc-2"
+}
+
+resource "fakewebservices_vpc" "dup_2" {
+  name       = "dup"
+  cidr_block = "10.2.0.0/16"
+}
+
+import {
+  to = fakewebservices_vpc.dup_2
+  id = "vpc-3"
+}
+
+resource "fakewebservices_server" "web_1" {
+  name = "Web 1"
+  type = "t2.micro"
+  vpc  = fakewebservices_vpc.main_vpc.name
+}
+
+import {
+  to = fakewebservices_server.web_1
+  id = "srv-1"
+}
+
+resource "fakewebservices_server" "web_1_2" {
+  name = "web-1"
+  type = "t2.micro"
+  vpc  = "dup"
+}
+
+import {
+  to = fakewebservices_server.web_1_2
+  id = "srv-2"
+}
+
+resource "fakewebservices_server" "_2nd" {
+  name = "2nd"
+  type = "t2.small"
+}
+
+import {
+  to = fakewebservices_server._2nd
+  id = "srv-3"
+}
+
+resource "fakewebservices_load_balancer" "load_balancer" {
+  servers = [fakewebservices_server.web_1.name, fakewebservices_server._2nd.name, "missing"]
+}
+
+import {
+  to = fakewebservices_load_balancer.load_balancer
+  id = "lb-1"
+}
+
+resource "fakewebservices_database" "db" {
+  name = "db"
+  size = 10
+}
+
+import {
+  to = fakewebservices_database.db
+  id = "db-1"
+}
+`
+	if got := string(e.file(true).Bytes()); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExport(t *testing.T) {
+	lists := map[string]string{
+		"vpcs":    `[{"type": "fake-resources-vpcs", "id": "vpc-1", "attributes": {"name": "main", "cidr_block": "10.0.0.0/16"}}]`,
+		"servers": `[{"type": "fake-resources-servers", "id": "srv-1", "attributes": {"name": "web", "server-type": "t2.micro", "vpc": "main"}}]`,
+	}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, ok := lists[strings.TrimPrefix(r.URL.Path, "/api/fake-resources/")]
+		if !ok {
+			list = "[]"
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": ` + list + `, "meta": {"pagination": {"current-page": 1, "total-pages": 1}}}`))
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{
+		"export", "-no-import", "vpcs", "servers",
+		"-hostname", strings.TrimPrefix(srv.URL, "https://"),
+		"-token", "test-token",
+		"-insecure-skip-verify",
+	}
+	if code := run(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+
+	want := `resource "fakewebservices_vpc" "main" {
+  name       = "main"
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "fakewebservices_server" "web" {
+  name = "web"
+  type = "t2.micro"
+  vpc  = fakewebservices_vpc.main.name
+}
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command fwsctl inspects and manages Fake Web Services objects without
+// writing Terraform configuration:
+//
+//	fwsctl list servers
+//	fwsctl get servers srv-1a2b3c4d -o yaml
+//	fwsctl create vpcs name=main cidr_block=10.0.0.0/16
+//	fwsctl delete servers srv-1a2b3c4d
+//	fwsctl export > imported.tf
+//	fwsctl drift -state terraform.tfstate
+//
+// It finds the API the same way the provider does: the hostname comes from
+// FWS_HOSTNAME, and the token from the Terraform CLI credentials file.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+	"github.com/hashicorp/terraform-provider-fakewebservices/fws"
+)
+
+// command is an fwsctl subcommand.
+type command struct {
+	usage   string
+	summary string
+	run     func(ctx context.Context, cli *cli, args []string) error
+}
+
+var commands = map[string]*command{
+	"list": {
+		usage:   "list <type>",
+		summary: "List every object of a type.",
+		run:     runList,
+	},
+	"get": {
+		usage:   "get <type> <id>...",
+		summary: "Show objects by ID.",
+		run:     runGet,
+	},
+	"create": {
+		usage:   "create <type> <name>=<value>...",
+		summary: "Create an object. List attributes, such as a load balancer's servers, are comma-separated.",
+		run:     runCreate,
+	},
+	"delete": {
+		usage:   "delete <type> <id>...",
+		summary: "Delete objects by ID.",
+		run:     runDelete,
+	},
+	"export": {
+		usage:   "export [type...]",
+		summary: "Print Terraform configuration, with import blocks, for every object of the types, or of every type.",
+		run:     runExport,
+	},
+	"nuke": {
+		usage:   "nuke -yes|-dry-run",
+		summary: "Delete every object, after the objects referencing it.",
+		run:     runNuke,
+	},
+	"drift": {
+		usage:   "drift [-state terraform.tfstate]",
+		summary: "Compare the objects in a Terraform state file with the API, reporting changed attributes and missing objects.",
+		run:     runDrift,
+	},
+}
+
+// errUsage is returned by commands given the wrong arguments, once the
+// command's usage has been printed.
+var errUsage = errors.New("usage")
+
+func main() {
+	// The provider's credentials lookup logs problems reading the
+	// credentials file, which surface here as a missing token instead.
+	log.SetOutput(io.Discard)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "fwsctl: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	cli := &cli{name: args[0], cmd: cmd, stdout: stdout, stderr: stderr}
+	err := cmd.run(ctx, cli, args[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "fwsctl: %v\n", err)
+		return 1
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: fwsctl <command> [flags] [args]\n\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-36s %s\n", commands[name].usage, commands[name].summary)
+	}
+
+	fmt.Fprintf(w, "\nTypes: %s\n\nRun fwsctl <command> -help for the command's flags.\n", strings.Join(typeNames(), ", "))
+}
+
+// cli holds the state of a command run: its flags, and where it writes.
+type cli struct {
+	name   string
+	cmd    *command
+	stdout io.Writer
+	stderr io.Writer
+
+	hostname           string
+	token              string
+	caCertFile         string
+	insecureSkipVerify bool
+	output             string
+}
+
+// flags returns a flag set for the command holding the connection flags,
+// and the output flag if the command prints objects.
+func (cli *cli) flags(output bool) *flag.FlagSet {
+	fs := flag.NewFlagSet(cli.name, flag.ContinueOnError)
+	fs.SetOutput(cli.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(cli.stderr, "Usage: fwsctl %s\n\n%s\n\nFlags:\n", cli.cmd.usage, cli.cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&cli.hostname, "hostname", fws.DefaultHostname(), "the API hostname; FWS_HOSTNAME sets the default")
+	fs.StringVar(&cli.token, "token", "", "the API token; defaults to the app.terraform.io token in the Terraform CLI credentials file")
+	fs.StringVar(&cli.caCertFile, "ca-cert-file", "", "PEM-encoded CA bundle to trust in addition to the system roots")
+	fs.BoolVar(&cli.insecureSkipVerify, "insecure-skip-verify", false, "skip verification of the API's TLS certificate")
+	if output {
+		fs.StringVar(&cli.output, "o", "table", "output format: table, json or yaml")
+	}
+
+	return fs
+}
+
+// parse parses args with fs, allowing flags after positional arguments,
+// and returns the positional arguments.
+func (cli *cli) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		// The flag set has already printed the error and usage.
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		if args[0] == "--" {
+			positional = append(positional, args[1:]...)
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	switch cli.output {
+	case "", "table", "json", "yaml":
+	default:
+		return nil, cli.usageError(fs, "unknown output format %q", cli.output)
+	}
+
+	return positional, nil
+}
+
+// usageError prints an error and the command's usage.
+func (cli *cli) usageError(fs *flag.FlagSet, format string, a ...interface{}) error {
+	fmt.Fprintf(cli.stderr, "fwsctl %s: %s\n\n", cli.name, fmt.Sprintf(format, a...))
+	fs.Usage()
+	return errUsage
+}
+
+// client configures an API client from the flags.
+func (cli *cli) client() (*client.Client, error) {
+	token := cli.token
+	if token == "" {
+		token = fws.DefaultToken()
+	}
+	if token == "" {
+		return nil, errors.New("no API token: pass -token, or run terraform login to save one to the Terraform CLI credentials file")
+	}
+
+	opts := []client.Option{
+		client.WithUserAgent("fwsctl"),
+	}
+
+	tlsConfig, err := client.NewTLSConfig(client.TLSOptions{
+		CACertFile:         cli.caCertFile,
+		InsecureSkipVerify: cli.insecureSkipVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		opts = append(opts, client.WithTLSConfig(tlsConfig))
+	}
+
+	c, err := client.NewClient(cli.hostname, token, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests are logged for the provider's debug logs, which would only
+	// clutter fwsctl's output.
+	c.HTTPClient.Logger = nil
+
+	return c, nil
+}
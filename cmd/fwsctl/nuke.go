@@ -0,0 +1,211 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+func runNuke(ctx context.Context, cli *cli, args []string) error {
+	fs := cli.flags(false)
+	dryRun := fs.Bool("dry-run", false, "print what would be deleted, in order, without deleting anything")
+	yes := fs.Bool("yes", false, "delete without asking; required unless -dry-run is set")
+	parallelism := fs.Int("parallelism", 10, "the most objects to delete at once")
+	args, err := cli.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return cli.usageError(fs, "unexpected arguments: nuke always deletes every object")
+	}
+	if *parallelism < 1 {
+		return cli.usageError(fs, "-parallelism must be at least 1")
+	}
+	if !*dryRun && !*yes {
+		return cli.usageError(fs, "nuke deletes every object the token can see; pass -yes to confirm, or -dry-run to see what would be deleted")
+	}
+
+	c, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	nodes, err := buildGraph(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		for _, n := range deletionOrder(nodes) {
+			fmt.Fprintf(cli.stdout, "Would delete %s\n", n)
+		}
+		fmt.Fprintf(cli.stdout, "%d objects would be deleted.\n", len(nodes))
+		return nil
+	}
+
+	return nuke(ctx, cli, c, nodes, *parallelism)
+}
+
+// node is an object in the dependency graph. An object can only be deleted
+// once every object referencing it has been.
+type node struct {
+	rt     *resourceType
+	record record
+
+	// references are the objects this object references.
+	references []*node
+
+	// referrers counts the objects referencing this object that have not
+	// been deleted yet.
+	referrers int
+}
+
+func (n *node) id() string {
+	return n.record["id"].(string)
+}
+
+func (n *node) String() string {
+	return fmt.Sprintf("%s %s (%s)", n.rt.name, n.id(), n.record["name"])
+}
+
+// buildGraph lists every object and links each to the objects it
+// references by name. A name shared by several objects references all of
+// them.
+func buildGraph(ctx context.Context, c *client.Client) ([]*node, error) {
+	var nodes []*node
+	byName := map[string]map[string][]*node{}
+
+	for _, rt := range resourceTypes {
+		byName[rt.name] = map[string][]*node{}
+		for r, err := range rt.list(ctx, c) {
+			if err != nil {
+				return nil, fmt.Errorf("error listing %s: %w", rt.name, err)
+			}
+
+			n := &node{rt: rt, record: r}
+			nodes = append(nodes, n)
+
+			name, _ := r["name"].(string)
+			byName[rt.name][name] = append(byName[rt.name][name], n)
+		}
+	}
+
+	for _, n := range nodes {
+		for attr, target := range n.rt.references {
+			var names []string
+			switch v := n.record[attr].(type) {
+			case string:
+				names = []string{v}
+			case []string:
+				names = v
+			}
+
+			for _, name := range names {
+				for _, ref := range byName[target][name] {
+					n.references = append(n.references, ref)
+					ref.referrers++
+				}
+			}
+		}
+	}
+
+	return nodes, nil
+}
+
+// deletionOrder returns nodes in an order they can be deleted in one at a
+// time: every object after the objects referencing it.
+func deletionOrder(nodes []*node) []*node {
+	referrers := make(map[*node]int, len(nodes))
+	var order []*node
+	for _, n := range nodes {
+		referrers[n] = n.referrers
+		if n.referrers == 0 {
+			order = append(order, n)
+		}
+	}
+
+	for i := 0; i < len(order); i++ {
+		for _, ref := range order[i].references {
+			referrers[ref]--
+			if referrers[ref] == 0 {
+				order = append(order, ref)
+			}
+		}
+	}
+
+	return order
+}
+
+// nuke deletes nodes, up to parallelism at once, deleting each object once
+// the objects referencing it are gone. Objects that are already gone count
+// as deleted. Objects referenced by an object that failed to delete are
+// left alone.
+func nuke(ctx context.Context, cli *cli, c *client.Client, nodes []*node, parallelism int) error {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, parallelism)
+		deleted int
+		errs    []error
+	)
+
+	var schedule func(n *node)
+	schedule = func(n *node) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			err := deleteObject(ctx, c, n.rt, n.id())
+			<-sem
+
+			mu.Lock()
+			if err != nil && !errors.Is(err, client.ErrResourceNotFound) {
+				errs = append(errs, fmt.Errorf("error deleting %s: %w", n, err))
+				mu.Unlock()
+				return
+			}
+			deleted++
+			fmt.Fprintf(cli.stdout, "Deleted %s\n", n)
+
+			var ready []*node
+			for _, ref := range n.references {
+				ref.referrers--
+				if ref.referrers == 0 {
+					ready = append(ready, ref)
+				}
+			}
+			mu.Unlock()
+
+			for _, ref := range ready {
+				schedule(ref)
+			}
+		}()
+	}
+
+	// Find the first objects to delete before any deletion starts changing
+	// the counts.
+	var ready []*node
+	for _, n := range nodes {
+		if n.referrers == 0 {
+			ready = append(ready, n)
+		}
+	}
+	for _, n := range ready {
+		schedule(n)
+	}
+	wg.Wait()
+
+	fmt.Fprintf(cli.stdout, "%d of %d objects deleted.\n", deleted, len(nodes))
+	if skipped := len(nodes) - deleted - len(errs); skipped > 0 {
+		errs = append(errs, fmt.Errorf("left %d objects in place, as objects referencing them could not be deleted", skipped))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+func TestNuke_order(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		deleted = map[string]int{}
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		deleted[strings.TrimPrefix(r.URL.Path, "/api/fake-resources/")] = len(deleted)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	c, err := client.NewClient(strings.TrimPrefix(srv.URL, "https://"), "test-token",
+		client.WithTLSConfig(&tls.Config{RootCAs: roots}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.HTTPClient.Logger = nil
+
+	newNode := func(typeName, id string, references ...*node) *node {
+		rt, err := lookupType(typeName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := &node{rt: rt, record: record{"id": id, "name": id}, references: references}
+		for _, ref := range references {
+			ref.referrers++
+		}
+		return n
+	}
+
+	// Load balancers each holding a server in one VPC, and a standalone
+	// database. Referenced objects come last, so deletions are under way
+	// by the time nuke reaches them.
+	vpc := newNode("vpcs", "vpc-1")
+	var nodes []*node
+	for i := range 10 {
+		server := newNode("servers", fmt.Sprintf("srv-%d", i), vpc)
+		lb := newNode("load_balancers", fmt.Sprintf("lb-%d", i), server)
+		nodes = append(nodes, lb, server)
+	}
+	nodes = append(nodes, newNode("databases", "db-1"), vpc)
+
+	stdout := &bytes.Buffer{}
+	if err := nuke(context.Background(), &cli{stdout: stdout}, c, nodes, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deleted) != len(nodes) {
+		t.Fatalf("deleted %d objects, want %d", len(deleted), len(nodes))
+	}
+	for _, n := range nodes {
+		path := n.rt.name + "/" + n.id()
+		for _, ref := range n.references {
+			refPath := ref.rt.name + "/" + ref.id()
+			if deleted[refPath] < deleted[path] {
+				t.Errorf("%s was deleted before %s, which references it", refPath, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// print writes records in the output format. With single, JSON and YAML
+// output holds the one record rather than a list.
+func (cli *cli) print(rt *resourceType, records []record, single bool) error {
+	var v interface{} = records
+	if single {
+		v = records[0]
+	}
+
+	switch cli.output {
+	case "json":
+		enc := json.NewEncoder(cli.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+
+	case "yaml":
+		enc := yaml.NewEncoder(cli.stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+
+	default:
+		return cli.printTable(rt.columns, records)
+	}
+}
+
+// printTable writes records as a table with the given columns.
+func (cli *cli) printTable(columns []string, records []record) error {
+	w := tabwriter.NewWriter(cli.stdout, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, r := range records {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = formatCell(r[column])
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+
+	return w.Flush()
+}
+
+func formatCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
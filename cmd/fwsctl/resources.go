@@ -0,0 +1,342 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+	"github.com/hashicorp/terraform-provider-fakewebservices/fws"
+)
+
+// record is an object as fwsctl shows it, keyed by the attribute names of
+// the matching Terraform resource.
+type record map[string]interface{}
+
+// resourceType is a kind of object fwsctl manages.
+type resourceType struct {
+	// name is the type's API path, which is also its name on the command
+	// line, such as "load_balancers".
+	name string
+
+	// terraformType is the Terraform resource type managing the objects.
+	terraformType string
+
+	// columns are the attributes shown in tables, in order.
+	columns []string
+
+	// references maps the attributes holding the names of other objects
+	// to the type of those objects.
+	references map[string]string
+
+	// include is the JSON:API relationship get -include reads along with
+	// each object, and included is the type of the objects it holds. The
+	// record of an object read with include lists them under "included".
+	include  string
+	included string
+
+	list   func(ctx context.Context, c *client.Client) iter.Seq2[record, error]
+	get    func(ctx context.Context, c *client.Client, id string, include bool) (record, error)
+	create func(ctx context.Context, c *client.Client, a *attributes) (record, error)
+}
+
+// resourceTypes are the types fwsctl manages. Types come before the types
+// whose objects reference them.
+var resourceTypes []*resourceType
+
+func init() {
+	define(
+		resourceType{
+			name:          "vpcs",
+			terraformType: "fakewebservices_vpc",
+			columns:       []string{"id", "name", "cidr_block"},
+		},
+		fws.Vpcs,
+		vpcRecord,
+		func(a *attributes) *fws.VpcCreateOptions {
+			return &fws.VpcCreateOptions{
+				Name:      a.required("name"),
+				CidrBlock: a.required("cidr_block"),
+			}
+		},
+	)
+
+	define(
+		resourceType{
+			name:          "servers",
+			terraformType: "fakewebservices_server",
+			columns:       []string{"id", "name", "type", "vpc"},
+			references:    map[string]string{"vpc": "vpcs"},
+			include:       fws.ServerIncludeVpc,
+			included:      "vpcs",
+		},
+		fws.Servers,
+		serverRecord,
+		func(a *attributes) *fws.ServerCreateOptions {
+			return &fws.ServerCreateOptions{
+				Name: a.required("name"),
+				Type: a.required("type"),
+				VPC:  client.String(a.optional("vpc")),
+			}
+		},
+	)
+
+	define(
+		resourceType{
+			name:          "load_balancers",
+			terraformType: "fakewebservices_load_balancer",
+			columns:       []string{"id", "name", "servers"},
+			references:    map[string]string{"servers": "servers"},
+			include:       fws.LoadBalancerIncludeServers,
+			included:      "servers",
+		},
+		fws.LoadBalancers,
+		func(lb *fws.LoadBalancer) record {
+			servers := lb.Servers
+			if servers == nil {
+				servers = []string{}
+			}
+			r := record{"id": lb.ID, "name": lb.Name, "servers": servers}
+			if lb.AttachedServers != nil {
+				included := make([]record, 0, len(lb.AttachedServers))
+				for _, server := range lb.AttachedServers {
+					included = append(included, serverRecord(server))
+				}
+				r["included"] = included
+			}
+			return r
+		},
+		func(a *attributes) *fws.LoadBalancerCreateOptions {
+			servers := a.list("servers")
+			return &fws.LoadBalancerCreateOptions{
+				Name:    a.required("name"),
+				Servers: &servers,
+			}
+		},
+	)
+
+	define(
+		resourceType{
+			name:          "databases",
+			terraformType: "fakewebservices_database",
+			columns:       []string{"id", "name", "size"},
+		},
+		fws.Databases,
+		func(database *fws.Database) record {
+			return record{"id": database.ID, "name": database.Name, "size": database.Size}
+		},
+		func(a *attributes) *fws.DatabaseCreateOptions {
+			opts := &fws.DatabaseCreateOptions{
+				Name: a.required("name"),
+				Size: a.requiredInt("size"),
+			}
+			if password := a.optional("password"); password != "" {
+				opts.Password = client.String(password)
+			}
+			return opts
+		},
+	)
+}
+
+func vpcRecord(vpc *fws.Vpc) record {
+	return record{"id": vpc.ID, "name": vpc.Name, "cidr_block": vpc.CidrBlock}
+}
+
+func serverRecord(server *fws.Server) record {
+	r := record{"id": server.ID, "name": server.Name, "type": server.Type, "vpc": server.VPC}
+	if server.ParentVpc != nil {
+		r["included"] = []record{vpcRecord(server.ParentVpc)}
+	}
+	return r
+}
+
+// define adds rt, whose objects are read into M and created from O.
+func define[M, O any](
+	rt resourceType,
+	collection func(*client.Client) client.Collection[*M],
+	toRecord func(*M) record,
+	createOptions func(*attributes) O,
+) {
+	rt.list = func(ctx context.Context, c *client.Client) iter.Seq2[record, error] {
+		return func(yield func(record, error) bool) {
+			for obj, err := range collection(c).All(ctx, &client.ListOptions{PageSize: 100}) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(toRecord(obj), nil) {
+					return
+				}
+			}
+		}
+	}
+
+	rt.get = func(ctx context.Context, c *client.Client, id string, include bool) (record, error) {
+		var opts interface{}
+		if include && rt.include != "" {
+			opts = client.ReadOptions{Include: []string{rt.include}}
+		}
+
+		req, err := c.NewRequest("GET", fmt.Sprintf("%s/%s", rt.name, id), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		obj := new(M)
+		if err := c.Do(ctx, req, obj); err != nil {
+			return nil, err
+		}
+		return toRecord(obj), nil
+	}
+
+	rt.create = func(ctx context.Context, c *client.Client, a *attributes) (record, error) {
+		opts := createOptions(a)
+		if err := a.err(); err != nil {
+			return nil, err
+		}
+
+		obj, err := client.Create[O, *M](ctx, c, rt.name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return toRecord(obj), nil
+	}
+
+	resourceTypes = append(resourceTypes, &rt)
+}
+
+// lookupType returns the resource type with the given name. The singular,
+// such as "server", is accepted too.
+func lookupType(name string) (*resourceType, error) {
+	for _, rt := range resourceTypes {
+		if rt.name == name || rt.name == name+"s" {
+			return rt, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown resource type %q; must be one of %s", name, strings.Join(typeNames(), ", "))
+}
+
+// lookupTerraformType returns the resource type managed by the given
+// Terraform resource type, or nil if there is none.
+func lookupTerraformType(terraformType string) *resourceType {
+	for _, rt := range resourceTypes {
+		if rt.terraformType == terraformType {
+			return rt
+		}
+	}
+	return nil
+}
+
+// lookupTypes returns the resource types with the given names, in the
+// order of resourceTypes. No names means every type.
+func lookupTypes(names []string) ([]*resourceType, error) {
+	if len(names) == 0 {
+		return resourceTypes, nil
+	}
+
+	selected := map[*resourceType]bool{}
+	for _, name := range names {
+		rt, err := lookupType(name)
+		if err != nil {
+			return nil, err
+		}
+		selected[rt] = true
+	}
+
+	var types []*resourceType
+	for _, rt := range resourceTypes {
+		if selected[rt] {
+			types = append(types, rt)
+		}
+	}
+	return types, nil
+}
+
+// typeNames returns the names of the resource types.
+func typeNames() []string {
+	names := make([]string, 0, len(resourceTypes))
+	for _, rt := range resourceTypes {
+		names = append(names, rt.name)
+	}
+	return names
+}
+
+// attributes are the name=value arguments given to create. Its methods
+// record the problems they find, which err returns along with any
+// attribute that was never asked for.
+type attributes struct {
+	values map[string]string
+	used   map[string]bool
+	errs   []string
+}
+
+// parseAttributes parses name=value arguments.
+func parseAttributes(args []string) (*attributes, error) {
+	a := &attributes{values: map[string]string{}, used: map[string]bool{}}
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return a, fmt.Errorf("invalid attribute %q; attributes must be given as name=value", arg)
+		}
+		a.values[name] = value
+	}
+	return a, nil
+}
+
+func (a *attributes) optional(name string) string {
+	a.used[name] = true
+	return a.values[name]
+}
+
+func (a *attributes) required(name string) *string {
+	v := a.optional(name)
+	if v == "" {
+		a.errs = append(a.errs, fmt.Sprintf("the %s attribute is required", name))
+	}
+	return client.String(v)
+}
+
+func (a *attributes) requiredInt(name string) *int {
+	v := a.required(name)
+	if *v == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(*v)
+	if err != nil {
+		a.errs = append(a.errs, fmt.Sprintf("the %s attribute must be a whole number", name))
+	}
+	return client.Int(n)
+}
+
+// list returns a comma-separated list attribute.
+func (a *attributes) list(name string) []string {
+	items := []string{}
+	for _, item := range strings.Split(a.optional(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (a *attributes) err() error {
+	for name := range a.values {
+		if !a.used[name] {
+			a.errs = append(a.errs, fmt.Sprintf("unsupported attribute %q", name))
+		}
+	}
+	if len(a.errs) == 0 {
+		return nil
+	}
+
+	slices.Sort(a.errs)
+	return errors.New(strings.Join(a.errs, "; "))
+}
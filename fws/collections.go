@@ -0,0 +1,27 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import "github.com/hashicorp/terraform-provider-fakewebservices/client"
+
+// Servers returns the collection of every server visible to c's token.
+func Servers(c *client.Client) client.Collection[*Server] {
+	return client.NewCollection[*Server](c, "servers")
+}
+
+// Databases returns the collection of every database visible to c's token.
+func Databases(c *client.Client) client.Collection[*Database] {
+	return client.NewCollection[*Database](c, "databases")
+}
+
+// LoadBalancers returns the collection of every load balancer visible to
+// c's token.
+func LoadBalancers(c *client.Client) client.Collection[*LoadBalancer] {
+	return client.NewCollection[*LoadBalancer](c, "load_balancers")
+}
+
+// Vpcs returns the collection of every VPC visible to c's token.
+func Vpcs(c *client.Client) client.Collection[*Vpc] {
+	return client.NewCollection[*Vpc](c, "vpcs")
+}
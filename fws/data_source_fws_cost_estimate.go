@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &costEstimateDataSource{}
+	_ datasource.DataSourceWithConfigure = &costEstimateDataSource{}
+)
+
+type costEstimateDataSource struct {
+	client *client.Client
+}
+
+type costEstimateDataSourceModel struct {
+	TotalMonthlyCost types.Float64             `tfsdk:"total_monthly_cost"`
+	ByResourceType   map[string]costTotalModel `tfsdk:"by_resource_type"`
+	ByTag            map[string]costTotalModel `tfsdk:"by_tag"`
+}
+
+type costTotalModel struct {
+	Count       types.Int64   `tfsdk:"count"`
+	MonthlyCost types.Float64 `tfsdk:"monthly_cost"`
+}
+
+// costTotalType is the type of the resource counts and costs in the
+// estimate's breakdowns.
+var costTotalType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"count":        types.Int64Type,
+		"monthly_cost": types.Float64Type,
+	},
+}
+
+// NewCostEstimateDataSource is a helper function to simplify the provider implementation.
+func NewCostEstimateDataSource() datasource.DataSource {
+	return &costEstimateDataSource{}
+}
+
+func (d *costEstimateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cost_estimate"
+}
+
+func (d *costEstimateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Estimates the monthly cost, in US dollars, of every server, database and load balancer visible to the provider's token.",
+		Attributes: map[string]schema.Attribute{
+			"total_monthly_cost": schema.Float64Attribute{
+				Description: "The estimated monthly cost of all resources.",
+				Computed:    true,
+			},
+			"by_resource_type": schema.MapAttribute{
+				Description: "The number of resources and their estimated monthly cost, keyed by resource type.",
+				Computed:    true,
+				ElementType: costTotalType,
+			},
+			"by_tag": schema.MapAttribute{
+				Description: "The number of resources and their estimated monthly cost, keyed by tag as key=value. " +
+					"A resource is counted under each of its tags, so these need not add up to the total.",
+				Computed:    true,
+				ElementType: costTotalType,
+			},
+		},
+	}
+}
+
+func (d *costEstimateDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	fwsClient, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = fwsClient
+}
+
+// costEstimateListOptions lists objects of the given JSON:API type, a full
+// page at a time, returning only the fields the estimate needs.
+func costEstimateListOptions(jsonapiType string, fields ...string) *client.ListOptions {
+	return &client.ListOptions{
+		PageSize: 100,
+		Fields:   map[string][]string{jsonapiType: fields},
+	}
+}
+
+func (d *costEstimateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	estimate := newCostEstimate()
+
+	log.Printf("[DEBUG] Listing servers for cost estimate")
+	for server, err := range Servers(d.client).All(ctx, costEstimateListOptions("fake-resources-servers", "server-type", "tags")) {
+		if err != nil {
+			resp.Diagnostics.AddError("Error listing servers", err.Error())
+			return
+		}
+
+		cost, ok := serverMonthlyCost(server.Type)
+		if !ok {
+			resp.Diagnostics.AddWarning(
+				"Unknown server type",
+				fmt.Sprintf("Server %s has type %q, which has no price. It is not included in the estimate.", server.ID, server.Type),
+			)
+		}
+		estimate.add("fakewebservices_server", server.Tags, cost)
+	}
+
+	log.Printf("[DEBUG] Listing databases for cost estimate")
+	for database, err := range Databases(d.client).All(ctx, costEstimateListOptions("fake-resources-databases", "size", "tags")) {
+		if err != nil {
+			resp.Diagnostics.AddError("Error listing databases", err.Error())
+			return
+		}
+
+		estimate.add("fakewebservices_database", database.Tags, databaseMonthlyCost(database.Size))
+	}
+
+	log.Printf("[DEBUG] Listing load_balancers for cost estimate")
+	for lb, err := range LoadBalancers(d.client).All(ctx, costEstimateListOptions("fake-resources-load-balancers", "tags")) {
+		if err != nil {
+			resp.Diagnostics.AddError("Error listing load_balancers", err.Error())
+			return
+		}
+
+		estimate.add("fakewebservices_load_balancer", lb.Tags, loadBalancerMonthlyCost())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, estimate.model())...)
+}
+
+// costEstimate adds up the monthly cost of resources, in total and broken
+// down by resource type and by tag.
+type costEstimate struct {
+	total  float64
+	byType map[string]*costTotal
+	byTag  map[string]*costTotal
+}
+
+type costTotal struct {
+	count int
+	cost  float64
+}
+
+func newCostEstimate() *costEstimate {
+	// Every resource type is listed, even when there are none.
+	return &costEstimate{
+		byType: map[string]*costTotal{
+			"fakewebservices_server":        {},
+			"fakewebservices_database":      {},
+			"fakewebservices_load_balancer": {},
+		},
+		byTag: map[string]*costTotal{},
+	}
+}
+
+// add counts a resource of the given type that costs cost a month, under
+// its type and under each of its tags, keyed as "key=value".
+func (e *costEstimate) add(resourceType string, tags map[string]interface{}, cost float64) {
+	e.total += cost
+
+	t := e.byType[resourceType]
+	if t == nil {
+		t = &costTotal{}
+		e.byType[resourceType] = t
+	}
+	t.count++
+	t.cost += cost
+
+	for k, v := range stringTags(tags) {
+		key := k + "=" + v
+		t := e.byTag[key]
+		if t == nil {
+			t = &costTotal{}
+			e.byTag[key] = t
+		}
+		t.count++
+		t.cost += cost
+	}
+}
+
+func (e *costEstimate) model() costEstimateDataSourceModel {
+	return costEstimateDataSourceModel{
+		TotalMonthlyCost: types.Float64Value(e.total),
+		ByResourceType:   costTotalModels(e.byType),
+		ByTag:            costTotalModels(e.byTag),
+	}
+}
+
+func costTotalModels(totals map[string]*costTotal) map[string]costTotalModel {
+	m := make(map[string]costTotalModel, len(totals))
+	for k, t := range totals {
+		m[k] = costTotalModel{
+			Count:       types.Int64Value(int64(t.count)),
+			MonthlyCost: types.Float64Value(t.cost),
+		}
+	}
+	return m
+}
@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+// newTestClient returns a client for the API served by handler.
+func newTestClient(t *testing.T, handler http.Handler) *client.Client {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	c, err := client.NewClient(strings.TrimPrefix(srv.URL, "https://"), "test-token", client.WithTLSConfig(&tls.Config{RootCAs: roots}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.HTTPClient.RetryMax = 0
+	c.HTTPClient.Logger = nil
+
+	return c
+}
+
+// listHandler serves each list in lists, a JSON array of resource objects
+// keyed by path, as a single page.
+func listHandler(lists map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, ok := lists[strings.TrimPrefix(r.URL.Path, "/api/fake-resources/")]
+		if r.Method != http.MethodGet || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": ` + list + `, "meta": {"pagination": {"current-page": 1, "total-pages": 1}}}`))
+	})
+}
+
+func TestCostEstimate(t *testing.T) {
+	e := newCostEstimate()
+	e.add("fakewebservices_server", map[string]interface{}{"env": "prod", "team": "web"}, 10)
+	e.add("fakewebservices_server", map[string]interface{}{"env": "dev"}, 5)
+	e.add("fakewebservices_database", map[string]interface{}{"env": "prod"}, 2.5)
+	e.add("fakewebservices_server", nil, 1)
+
+	if !closeTo(e.total, 18.5) {
+		t.Errorf("got total %v, want 18.5", e.total)
+	}
+
+	wantByType := map[string]costTotal{
+		"fakewebservices_server":        {count: 3, cost: 16},
+		"fakewebservices_database":      {count: 1, cost: 2.5},
+		"fakewebservices_load_balancer": {count: 0, cost: 0},
+	}
+	checkCostTotals(t, "type", e.byType, wantByType)
+
+	// Each resource counts under every one of its tags, and untagged
+	// resources under none.
+	wantByTag := map[string]costTotal{
+		"env=prod": {count: 2, cost: 12.5},
+		"env=dev":  {count: 1, cost: 5},
+		"team=web": {count: 1, cost: 10},
+	}
+	checkCostTotals(t, "tag", e.byTag, wantByTag)
+}
+
+func checkCostTotals(t *testing.T, by string, got map[string]*costTotal, want map[string]costTotal) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("got %d totals by %s, want %d", len(got), by, len(want))
+	}
+	for k, w := range want {
+		g := got[k]
+		if g == nil || g.count != w.count || !closeTo(g.cost, w.cost) {
+			t.Errorf("got %+v for %s %s, want %+v", g, by, k, w)
+		}
+	}
+}
+
+func TestCostEstimateDataSource_read(t *testing.T) {
+	ctx := context.Background()
+	d := &costEstimateDataSource{client: newTestClient(t, listHandler(map[string]string{
+		"servers": `[
+			{"type": "fake-resources-servers", "id": "srv-1", "attributes": {"server-type": "t2.micro", "tags": {"env": "prod"}}},
+			{"type": "fake-resources-servers", "id": "srv-2", "attributes": {"server-type": "t2.large"}},
+			{"type": "fake-resources-servers", "id": "srv-3", "attributes": {"server-type": "x1.huge", "tags": {"env": "prod"}}}
+		]`,
+		"databases": `[
+			{"type": "fake-resources-databases", "id": "db-1", "attributes": {"size": 64, "tags": {"env": "prod"}}}
+		]`,
+		"load_balancers": `[
+			{"type": "fake-resources-load-balancers", "id": "lb-1", "attributes": {"tags": {"env": "dev"}}}
+		]`,
+	}))}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			Schema: schemaResp.Schema,
+		},
+	}
+	d.Read(ctx, datasource.ReadRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	// The server of an unknown type is counted, but costs nothing.
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("got diagnostics %v, want a warning for the unknown server type", resp.Diagnostics)
+	}
+
+	var got costEstimateDataSourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// 8.468 + 67.744 for the servers, 7.36 for the database and 16.425
+	// for the load balancer.
+	if total := got.TotalMonthlyCost.ValueFloat64(); !closeTo(total, 99.997) {
+		t.Errorf("got total %v, want 99.997", total)
+	}
+
+	servers := got.ByResourceType["fakewebservices_server"]
+	if servers.Count.ValueInt64() != 3 || !closeTo(servers.MonthlyCost.ValueFloat64(), 76.212) {
+		t.Errorf("got servers %+v", servers)
+	}
+	prod := got.ByTag["env=prod"]
+	if prod.Count.ValueInt64() != 3 || !closeTo(prod.MonthlyCost.ValueFloat64(), 15.828) {
+		t.Errorf("got env=prod %+v", prod)
+	}
+	dev := got.ByTag["env=dev"]
+	if dev.Count.ValueInt64() != 1 || !closeTo(dev.MonthlyCost.ValueFloat64(), 16.425) {
+		t.Errorf("got env=dev %+v", dev)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ datasource.DataSource = &serverTypesDataSource{}
+
+type serverTypesDataSource struct{}
+
+type serverTypesDataSourceModel struct {
+	MinVCPU     types.Int64       `tfsdk:"min_vcpu"`
+	MinMemory   types.Float64     `tfsdk:"min_memory"`
+	Available   types.Bool        `tfsdk:"available"`
+	ServerTypes []serverTypeModel `tfsdk:"server_types"`
+}
+
+type serverTypeModel struct {
+	Name         types.String  `tfsdk:"name"`
+	VCPU         types.Int64   `tfsdk:"vcpu"`
+	Memory       types.Float64 `tfsdk:"memory"`
+	PricePerHour types.Float64 `tfsdk:"price_per_hour"`
+	Available    types.Bool    `tfsdk:"available"`
+}
+
+// NewServerTypesDataSource is a helper function to simplify the provider implementation.
+func NewServerTypesDataSource() datasource.DataSource {
+	return &serverTypesDataSource{}
+}
+
+func (d *serverTypesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_types"
+}
+
+func (d *serverTypesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the server types that can be used for fakewebservices_server, cheapest first.",
+		Attributes: map[string]schema.Attribute{
+			"min_vcpu": schema.Int64Attribute{
+				Description: "Only return server types with at least this many virtual CPUs.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"min_memory": schema.Float64Attribute{
+				Description: "Only return server types with at least this much memory, in GiB.",
+				Optional:    true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"available": schema.BoolAttribute{
+				Description: "Only return server types whose availability matches this value.",
+				Optional:    true,
+			},
+			"server_types": schema.ListAttribute{
+				Description: "The matching server types, ordered by price per hour. Each has a name, " +
+					"vcpu, memory in GiB, price_per_hour in US dollars, and whether it is available.",
+				Computed: true,
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"name":           types.StringType,
+						"vcpu":           types.Int64Type,
+						"memory":         types.Float64Type,
+						"price_per_hour": types.Float64Type,
+						"available":      types.BoolType,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *serverTypesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config serverTypesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var matches []ServerType
+	for _, t := range serverTypes {
+		if int64(t.VCPU) < config.MinVCPU.ValueInt64() {
+			continue
+		}
+		if t.Memory < config.MinMemory.ValueFloat64() {
+			continue
+		}
+		if !config.Available.IsNull() && t.Available != config.Available.ValueBool() {
+			continue
+		}
+		matches = append(matches, t)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].PricePerHour < matches[j].PricePerHour
+	})
+
+	config.ServerTypes = make([]serverTypeModel, 0, len(matches))
+	for _, t := range matches {
+		config.ServerTypes = append(config.ServerTypes, serverTypeModel{
+			Name:         types.StringValue(t.Name),
+			VCPU:         types.Int64Value(int64(t.VCPU)),
+			Memory:       types.Float64Value(t.Memory),
+			PricePerHour: types.Float64Value(t.PricePerHour),
+			Available:    types.BoolValue(t.Available),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
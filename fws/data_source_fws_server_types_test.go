@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestServerTypesDataSource_read(t *testing.T) {
+	cases := map[string]struct {
+		config map[string]tftypes.Value
+		want   []string
+	}{
+		"all, cheapest first": {
+			want: []string{
+				"t2.nano", "t2.micro", "t2.small", "t2.medium", "t2.large", "m5.large",
+				"t2.xlarge", "m5.xlarge", "t2.2xlarge", "m5.2xlarge", "m5.4xlarge",
+			},
+		},
+		"min vcpu": {
+			config: map[string]tftypes.Value{"min_vcpu": tftypes.NewValue(tftypes.Number, 8)},
+			want:   []string{"t2.2xlarge", "m5.2xlarge", "m5.4xlarge"},
+		},
+		"min memory and available": {
+			config: map[string]tftypes.Value{
+				"min_memory": tftypes.NewValue(tftypes.Number, 16),
+				"available":  tftypes.NewValue(tftypes.Bool, true),
+			},
+			want: []string{"t2.xlarge", "m5.xlarge", "t2.2xlarge", "m5.2xlarge"},
+		},
+		"fractional memory": {
+			config: map[string]tftypes.Value{"min_memory": tftypes.NewValue(tftypes.Number, 1.5)},
+			want: []string{
+				"t2.small", "t2.medium", "t2.large", "m5.large",
+				"t2.xlarge", "m5.xlarge", "t2.2xlarge", "m5.2xlarge", "m5.4xlarge",
+			},
+		},
+		"unavailable": {
+			config: map[string]tftypes.Value{"available": tftypes.NewValue(tftypes.Bool, false)},
+			want:   []string{"m5.4xlarge"},
+		},
+		"no matches": {
+			config: map[string]tftypes.Value{"min_vcpu": tftypes.NewValue(tftypes.Number, 64)},
+			want:   []string{},
+		},
+	}
+
+	// The catalog is built in, so the API is never called.
+	s := newTestProviderServer(t, http.NotFoundHandler())
+	schema := s.schema.DataSourceSchemas["fakewebservices_server_types"]
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s.t = t
+			resp, err := s.ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{
+				TypeName: "fakewebservices_server_types",
+				Config:   s.value(schema, tc.config),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.check(resp.Diagnostics)
+
+			var serverTypes []tftypes.Value
+			if err := s.attributes(schema, resp.State)["server_types"].As(&serverTypes); err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, v := range serverTypes {
+				var attrs map[string]tftypes.Value
+				var name string
+				if err := v.As(&attrs); err != nil {
+					t.Fatal(err)
+				}
+				if err := attrs["name"].As(&name); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, name)
+			}
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestServerTypesDataSource_validation(t *testing.T) {
+	cases := map[string]map[string]tftypes.Value{
+		"negative min vcpu":   {"min_vcpu": tftypes.NewValue(tftypes.Number, -1)},
+		"negative min memory": {"min_memory": tftypes.NewValue(tftypes.Number, -0.5)},
+	}
+
+	s := newTestProviderServer(t, http.NotFoundHandler())
+	schema := s.schema.DataSourceSchemas["fakewebservices_server_types"]
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp, err := s.ValidateDataSourceConfig(context.Background(), &tfprotov5.ValidateDataSourceConfigRequest{
+				TypeName: "fakewebservices_server_types",
+				Config:   s.value(schema, config),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Diagnostics) == 0 || resp.Diagnostics[0].Severity != tfprotov5.DiagnosticSeverityError {
+				t.Errorf("got diagnostics %v, want an error", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// The first match is the smallest, cheapest type that fits, which is
+// how modules are expected to pick one.
+func TestServerTypesDataSource_cheapestFit(t *testing.T) {
+	s := newTestProviderServer(t, http.NotFoundHandler())
+	schema := s.schema.DataSourceSchemas["fakewebservices_server_types"]
+
+	resp, err := s.ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{
+		TypeName: "fakewebservices_server_types",
+		Config: s.value(schema, map[string]tftypes.Value{
+			"min_vcpu":   tftypes.NewValue(tftypes.Number, 2),
+			"min_memory": tftypes.NewValue(tftypes.Number, 6),
+			"available":  tftypes.NewValue(tftypes.Bool, true),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.check(resp.Diagnostics)
+
+	var serverTypes []tftypes.Value
+	if err := s.attributes(schema, resp.State)["server_types"].As(&serverTypes); err != nil {
+		t.Fatal(err)
+	}
+	if len(serverTypes) == 0 {
+		t.Fatal("got no server types")
+	}
+	var first map[string]tftypes.Value
+	if err := serverTypes[0].As(&first); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]tftypes.Value{
+		"name":           tftypes.NewValue(tftypes.String, "t2.large"),
+		"vcpu":           tftypes.NewValue(tftypes.Number, 2),
+		"memory":         tftypes.NewValue(tftypes.Number, 8),
+		"price_per_hour": tftypes.NewValue(tftypes.Number, 0.0928),
+		"available":      tftypes.NewValue(tftypes.Bool, true),
+	}
+	for name, v := range want {
+		if !first[name].Equal(v) {
+			t.Errorf("got %s %v, want %v", name, first[name], v)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &databaseCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &databaseCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &databaseCredentialsEphemeralResource{}
+)
+
+// databaseCredentialsPrivateKey is the private data key holding the ID of
+// the credentials, so they can be revoked on close.
+const databaseCredentialsPrivateKey = "credentials"
+
+type databaseCredentialsEphemeralResource struct {
+	client *client.Client
+}
+
+type databaseCredentialsEphemeralResourceModel struct {
+	DatabaseID types.String `tfsdk:"database_id"`
+	TTL        types.Int64  `tfsdk:"ttl"`
+	Username   types.String `tfsdk:"username"`
+	Password   types.String `tfsdk:"password"`
+	ExpiresAt  types.String `tfsdk:"expires_at"`
+}
+
+type databaseCredentialsPrivateData struct {
+	DatabaseID    string `json:"database_id"`
+	CredentialsID string `json:"credentials_id"`
+}
+
+// NewDatabaseCredentialsEphemeralResource is a helper function to simplify the provider implementation.
+func NewDatabaseCredentialsEphemeralResource() ephemeral.EphemeralResource {
+	return &databaseCredentialsEphemeralResource{}
+}
+
+func (r *databaseCredentialsEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database_credentials"
+}
+
+func (r *databaseCredentialsEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Requests short-lived credentials for a database. The credentials are never stored in state or plan, " +
+			"and are revoked when Terraform is done with them.",
+		Attributes: map[string]schema.Attribute{
+			"database_id": schema.StringAttribute{
+				Description: "The ID of the database to request credentials for.",
+				Required:    true,
+			},
+			"ttl": schema.Int64Attribute{
+				Description: "How long the credentials are valid for, in seconds. Defaults to the API's default.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "The database username.",
+				Computed:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "The database password.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"expires_at": schema.StringAttribute{
+				Description: "When the credentials expire, in RFC 3339 format.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *databaseCredentialsEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	fwsClient, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = fwsClient
+}
+
+func (r *databaseCredentialsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var config databaseCredentialsEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	databaseID := config.DatabaseID.ValueString()
+
+	options := DatabaseCredentialsCreateOptions{}
+	if !config.TTL.IsNull() {
+		options.TTL = client.Int(int(config.TTL.ValueInt64()))
+	}
+
+	fwsReq, err := r.client.NewRequest(
+		"POST",
+		fmt.Sprintf("databases/%s/credentials", databaseID),
+		&options,
+	)
+	if err != nil {
+		resp.Diagnostics.AddError("Error requesting database credentials", err.Error())
+		return
+	}
+
+	creds := &DatabaseCredentials{}
+
+	log.Printf("[DEBUG] Requesting credentials for database: %s", databaseID)
+	err = r.client.Do(ctx, fwsReq, creds)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error requesting database credentials",
+			fmt.Sprintf("Error requesting credentials for database %s: %v", databaseID, err),
+		)
+		return
+	}
+
+	config.Username = types.StringValue(creds.Username)
+	config.Password = types.StringValue(creds.Password)
+	config.ExpiresAt = types.StringValue(creds.ExpiresAt.Format(time.RFC3339))
+
+	private, err := json.Marshal(databaseCredentialsPrivateData{
+		DatabaseID:    databaseID,
+		CredentialsID: creds.ID,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error requesting database credentials", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, databaseCredentialsPrivateKey, private)...)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, config)...)
+}
+
+func (r *databaseCredentialsEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	raw, diags := req.Private.GetKey(ctx, databaseCredentialsPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || raw == nil {
+		return
+	}
+
+	var private databaseCredentialsPrivateData
+	if err := json.Unmarshal(raw, &private); err != nil {
+		resp.Diagnostics.AddError("Error revoking database credentials", err.Error())
+		return
+	}
+
+	fwsReq, err := r.client.NewRequest(
+		"DELETE",
+		fmt.Sprintf("databases/%s/credentials/%s", private.DatabaseID, private.CredentialsID),
+		nil,
+	)
+	if err != nil {
+		resp.Diagnostics.AddError("Error revoking database credentials", err.Error())
+		return
+	}
+
+	log.Printf("[DEBUG] Revoking credentials %s for database: %s", private.CredentialsID, private.DatabaseID)
+	err = r.client.Do(ctx, fwsReq, nil)
+	if err != nil && err != client.ErrResourceNotFound {
+		resp.Diagnostics.AddError("Error revoking database credentials", err.Error())
+		return
+	}
+}
+
+type DatabaseCredentials struct {
+	ID        string    `jsonapi:"primary,fake-resources-database-credentials"`
+	Username  string    `jsonapi:"attr,username,omitempty"`
+	Password  string    `jsonapi:"attr,password,omitempty"`
+	ExpiresAt time.Time `jsonapi:"attr,expires-at,iso8601"`
+}
+
+type DatabaseCredentialsCreateOptions struct {
+	// For internal use only!
+	ID string `jsonapi:"primary,fake-resources-database-credentials"`
+
+	// How long the credentials are valid for, in seconds.
+	TTL *int `jsonapi:"attr,ttl,omitempty"`
+}
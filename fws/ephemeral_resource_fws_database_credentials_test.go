@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// apiRequest is a request received by a fakeAPI.
+type apiRequest struct {
+	method, path string
+
+	// attributes are the attributes of the request's resource object, if
+	// any.
+	attributes map[string]interface{}
+}
+
+type apiResponse struct {
+	status int
+	body   string
+}
+
+// fakeAPI answers each request with the response for its method and path
+// below /api/fake-resources/, such as "GET databases/db-1", and records
+// the requests it receives.
+type fakeAPI struct {
+	responses map[string]apiResponse
+
+	mu       sync.Mutex
+	requests []apiRequest
+}
+
+func (api *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := apiRequest{method: r.Method, path: strings.TrimPrefix(r.URL.Path, "/api/fake-resources/")}
+
+	var doc struct {
+		Data struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"data"`
+	}
+	if json.NewDecoder(r.Body).Decode(&doc) == nil {
+		req.attributes = doc.Data.Attributes
+	}
+
+	api.mu.Lock()
+	api.requests = append(api.requests, req)
+	api.mu.Unlock()
+
+	resp, ok := api.responses[req.method+" "+req.path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(resp.status)
+	w.Write([]byte(resp.body))
+}
+
+// received returns the requests received with the given method.
+func (api *fakeAPI) received(method string) []apiRequest {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var reqs []apiRequest
+	for _, req := range api.requests {
+		if req.method == method {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+func TestDatabaseCredentialsEphemeralResource(t *testing.T) {
+	api := &fakeAPI{responses: map[string]apiResponse{
+		"POST databases/db-1/credentials": {http.StatusCreated, `{"data": {"type": "fake-resources-database-credentials", "id": "cred-1",
+			"attributes": {"username": "user-1", "password": "secret", "expires-at": "2026-10-18T12:00:00Z"}}}`},
+		"DELETE databases/db-1/credentials/cred-1": {http.StatusNoContent, ""},
+	}}
+	s := newTestProviderServer(t, api)
+	ctx := context.Background()
+	schema := s.schema.EphemeralResourceSchemas["fakewebservices_database_credentials"]
+
+	open, err := s.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "fakewebservices_database_credentials",
+		Config: s.value(schema, map[string]tftypes.Value{
+			"database_id": tftypes.NewValue(tftypes.String, "db-1"),
+			"ttl":         tftypes.NewValue(tftypes.Number, 600),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.check(open.Diagnostics)
+
+	creates := api.received("POST")
+	if len(creates) != 1 || creates[0].attributes["ttl"] != float64(600) {
+		t.Fatalf("got requests %+v, want one POST with the TTL", creates)
+	}
+
+	result := s.attributes(schema, open.Result)
+	for name, want := range map[string]string{"username": "user-1", "password": "secret", "expires_at": "2026-10-18T12:00:00Z"} {
+		var got string
+		if err := result[name].As(&got); err != nil || got != want {
+			t.Errorf("got %s %q, want %q", name, got, want)
+		}
+	}
+
+	// Nothing is revoked until the credentials are closed.
+	if deletes := api.received("DELETE"); len(deletes) != 0 {
+		t.Fatalf("got %d DELETE requests before close", len(deletes))
+	}
+
+	closeResp, err := s.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: "fakewebservices_database_credentials",
+		Private:  open.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.check(closeResp.Diagnostics)
+
+	if deletes := api.received("DELETE"); len(deletes) != 1 || deletes[0].path != "databases/db-1/credentials/cred-1" {
+		t.Errorf("got DELETE requests %+v, want one for cred-1", deletes)
+	}
+}
+
+func TestDatabaseCredentialsEphemeralResource_closeAlreadyRevoked(t *testing.T) {
+	api := &fakeAPI{responses: map[string]apiResponse{
+		"POST databases/db-1/credentials": {http.StatusCreated, `{"data": {"type": "fake-resources-database-credentials", "id": "cred-1",
+			"attributes": {"username": "user-1", "password": "secret", "expires-at": "2026-10-18T12:00:00Z"}}}`},
+	}}
+	s := newTestProviderServer(t, api)
+	ctx := context.Background()
+	schema := s.schema.EphemeralResourceSchemas["fakewebservices_database_credentials"]
+
+	open, err := s.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "fakewebservices_database_credentials",
+		Config:   s.value(schema, map[string]tftypes.Value{"database_id": tftypes.NewValue(tftypes.String, "db-1")}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.check(open.Diagnostics)
+
+	// Credentials that have expired are gone already, which is not an
+	// error.
+	closeResp, err := s.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: "fakewebservices_database_credentials",
+		Private:  open.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.check(closeResp.Diagnostics)
+}
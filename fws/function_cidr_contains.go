@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &cidrContainsFunction{}
+
+type cidrContainsFunction struct{}
+
+// NewCidrContainsFunction is a helper function to simplify the provider implementation.
+func NewCidrContainsFunction() function.Function {
+	return &cidrContainsFunction{}
+}
+
+func (f *cidrContainsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cidr_contains"
+}
+
+func (f *cidrContainsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Check whether a CIDR block contains an address or another CIDR block",
+		Description: "Returns true if the given IP address, or every address in the given CIDR block, falls within the containing CIDR block.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cidr_block",
+				Description: "The containing CIDR block, such as a VPC's cidr_block.",
+			},
+			function.StringParameter{
+				Name:        "address",
+				Description: "An IP address or CIDR block to look for.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *cidrContainsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidrBlock, address string
+
+	resp.Error = req.Arguments.Get(ctx, &cidrBlock, &address)
+	if resp.Error != nil {
+		return
+	}
+
+	outer, err := parseCIDR(cidrBlock)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid CIDR block: %s", err))
+		return
+	}
+
+	var inner netip.Prefix
+	if strings.Contains(address, "/") {
+		inner, err = parseCIDR(address)
+	} else {
+		var addr netip.Addr
+		addr, err = netip.ParseAddr(address)
+		inner = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("Invalid address: %s", err))
+		return
+	}
+
+	contains := outer.Addr().Is4() == inner.Addr().Is4() &&
+		inner.Bits() >= outer.Bits() &&
+		outer.Contains(inner.Addr())
+
+	resp.Error = resp.Result.Set(ctx, contains)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &serverTypeSpecFunction{}
+
+type serverTypeSpecFunction struct{}
+
+type serverTypeSpecModel struct {
+	VCPU   types.Int64   `tfsdk:"vcpu"`
+	Memory types.Float64 `tfsdk:"memory"`
+}
+
+// NewServerTypeSpecFunction is a helper function to simplify the provider implementation.
+func NewServerTypeSpecFunction() function.Function {
+	return &serverTypeSpecFunction{}
+}
+
+func (f *serverTypeSpecFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "server_type_spec"
+}
+
+func (f *serverTypeSpecFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Look up the specification of a server type",
+		Description: "Returns an object with the number of virtual CPUs (vcpu) and the memory in GiB (memory) of the given server type.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "type",
+				Description: "The server type, such as t2.micro.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"vcpu":   types.Int64Type,
+				"memory": types.Float64Type,
+			},
+		},
+	}
+}
+
+func (f *serverTypeSpecFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+
+	resp.Error = req.Arguments.Get(ctx, &name)
+	if resp.Error != nil {
+		return
+	}
+
+	serverType, ok := lookupServerType(name)
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Unknown server type %q.", name))
+		return
+	}
+
+	spec := serverTypeSpecModel{
+		VCPU:   types.Int64Value(int64(serverType.VCPU)),
+		Memory: types.Float64Value(serverType.Memory),
+	}
+
+	resp.Error = resp.Result.Set(ctx, spec)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestServerTypeSpecFunction(t *testing.T) {
+	cases := map[string]struct {
+		name       string
+		wantVCPU   int64
+		wantMemory float64
+		wantErr    bool
+	}{
+		"smallest": {
+			name:       "t2.nano",
+			wantVCPU:   1,
+			wantMemory: 0.5,
+		},
+		"other family": {
+			name:       "m5.large",
+			wantVCPU:   2,
+			wantMemory: 8,
+		},
+		"unavailable": {
+			name:       "m5.4xlarge",
+			wantVCPU:   16,
+			wantMemory: 64,
+		},
+		"unknown": {
+			name:    "t3.micro",
+			wantErr: true,
+		},
+		"empty": {
+			name:    "",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(tc.name)}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(map[string]attr.Type{
+					"vcpu":   types.Int64Type,
+					"memory": types.Float64Type,
+				})),
+			}
+
+			(&serverTypeSpecFunction{}).Run(ctx, req, resp)
+
+			if tc.wantErr {
+				if resp.Error == nil {
+					t.Fatal("expected an error")
+				}
+				if resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0 {
+					t.Errorf("expected an error for the type argument, got %v", resp.Error)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %v", resp.Error)
+			}
+
+			var got serverTypeSpecModel
+			resp.Result.Value().(types.Object).As(ctx, &got, basetypes.ObjectAsOptions{})
+			if got.VCPU.ValueInt64() != tc.wantVCPU || got.Memory.ValueFloat64() != tc.wantMemory {
+				t.Errorf("got %d vCPUs and %g GiB, want %d and %g", got.VCPU.ValueInt64(), got.Memory.ValueFloat64(), tc.wantVCPU, tc.wantMemory)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &subnetPlanFunction{}
+
+// maxSubnetPlanCount is the most subnets subnet_plan returns, which keeps
+// a large count from exhausting the provider's memory.
+const maxSubnetPlanCount = 65536
+
+type subnetPlanFunction struct{}
+
+// NewSubnetPlanFunction is a helper function to simplify the provider implementation.
+func NewSubnetPlanFunction() function.Function {
+	return &subnetPlanFunction{}
+}
+
+func (f *subnetPlanFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "subnet_plan"
+}
+
+func (f *subnetPlanFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Split a CIDR block into equally sized subnets",
+		Description: "Splits a CIDR block into the given number of subnets. Every subnet has the same size, " +
+			"the largest that lets the requested number fit, and they are returned in address order.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cidr_block",
+				Description: "The CIDR block to split, such as a VPC's cidr_block.",
+			},
+			function.Int64Parameter{
+				Name:        "count",
+				Description: "The number of subnets to return, at most 65536.",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *subnetPlanFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidrBlock string
+	var count int64
+
+	resp.Error = req.Arguments.Get(ctx, &cidrBlock, &count)
+	if resp.Error != nil {
+		return
+	}
+
+	prefix, err := parseCIDR(cidrBlock)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid CIDR block: %s", err))
+		return
+	}
+
+	if count < 1 {
+		resp.Error = function.NewArgumentFuncError(1, "Count must be at least 1.")
+		return
+	}
+	if count > maxSubnetPlanCount {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("Count must be at most %d.", maxSubnetPlanCount))
+		return
+	}
+
+	// Borrow enough bits from the host part to number count subnets.
+	newBits := bits.Len64(uint64(count - 1))
+	subnetBits := prefix.Bits() + newBits
+	if subnetBits > prefix.Addr().BitLen() {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf(
+			"Cannot split %s into %d subnets.", prefix, count,
+		))
+		return
+	}
+
+	subnets := make([]string, 0, count)
+	for i := int64(0); i < count; i++ {
+		subnets = append(subnets, nthSubnet(prefix, subnetBits, i).String())
+	}
+
+	resp.Error = resp.Result.Set(ctx, subnets)
+}
+
+// nthSubnet returns the n-th subnet of size subnetBits within prefix.
+func nthSubnet(prefix netip.Prefix, subnetBits int, n int64) netip.Prefix {
+	base := new(big.Int).SetBytes(prefix.Addr().AsSlice())
+	offset := new(big.Int).Lsh(big.NewInt(n), uint(prefix.Addr().BitLen()-subnetBits))
+	base.Add(base, offset)
+
+	raw := make([]byte, prefix.Addr().BitLen()/8)
+	base.FillBytes(raw)
+
+	addr, _ := netip.AddrFromSlice(raw)
+	return netip.PrefixFrom(addr, subnetBits)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSubnetPlanFunction(t *testing.T) {
+	cases := map[string]struct {
+		cidrBlock string
+		count     int64
+		want      []string
+		wantErr   bool
+	}{
+		"split": {
+			cidrBlock: "10.0.0.0/16",
+			count:     3,
+			want:      []string{"10.0.0.0/18", "10.0.64.0/18", "10.0.128.0/18"},
+		},
+		"zero count": {
+			cidrBlock: "10.0.0.0/16",
+			count:     0,
+			wantErr:   true,
+		},
+		"too many for the block": {
+			cidrBlock: "10.0.0.0/30",
+			count:     5,
+			wantErr:   true,
+		},
+		"at the cap": {
+			cidrBlock: "::/0",
+			count:     maxSubnetPlanCount,
+		},
+		"above the cap": {
+			cidrBlock: "::/0",
+			count:     1 << 50,
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tc.cidrBlock),
+					types.Int64Value(tc.count),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(types.StringType)),
+			}
+
+			(&subnetPlanFunction{}).Run(ctx, req, resp)
+
+			if tc.wantErr {
+				if resp.Error == nil {
+					t.Fatal("expected an error")
+				}
+				if resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 1 {
+					t.Errorf("expected an error for the count argument, got %v", resp.Error)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %v", resp.Error)
+			}
+
+			var got []string
+			resp.Result.Value().(types.List).ElementsAs(ctx, &got, false)
+			if len(got) != int(tc.count) {
+				t.Fatalf("got %d subnets, want %d", len(got), tc.count)
+			}
+			if tc.want != nil && !slices.Equal(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Importing an object by ID and reading it fills in the attributes that
+// otherwise only come from the configuration.
+func TestImport(t *testing.T) {
+	api := &fakeAPI{responses: map[string]apiResponse{
+		"GET vpcs/vpc-1": {http.StatusOK, `{"data": {"type": "fake-resources-vpcs", "id": "vpc-1",
+			"attributes": {"name": "main", "cidr_block": "10.0.0.0/16"}}}`},
+		"GET servers/srv-1": {http.StatusOK, `{"data": {"type": "fake-resources-servers", "id": "srv-1",
+			"attributes": {"name": "web", "server-type": "t2.micro", "vpc": "main"}}}`},
+		"GET load_balancers/lb-1": {http.StatusOK, `{"data": {"type": "fake-resources-load-balancers", "id": "lb-1",
+			"attributes": {"name": "lb", "servers": ["web"]}}}`},
+		"GET databases/db-1": {http.StatusOK, `{"data": {"type": "fake-resources-databases", "id": "db-1",
+			"attributes": {"name": "db", "size": 20, "tags": {"env": "prod"}}}}`},
+	}}
+	s := newTestProviderServer(t, api)
+
+	cases := map[string]struct {
+		id   string
+		want map[string]tftypes.Value
+	}{
+		"fakewebservices_vpc": {
+			id: "vpc-1",
+			want: map[string]tftypes.Value{
+				"name":       tftypes.NewValue(tftypes.String, "main"),
+				"cidr_block": tftypes.NewValue(tftypes.String, "10.0.0.0/16"),
+			},
+		},
+		"fakewebservices_server": {
+			id: "srv-1",
+			want: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "web"),
+				"type": tftypes.NewValue(tftypes.String, "t2.micro"),
+				"vpc":  tftypes.NewValue(tftypes.String, "main"),
+			},
+		},
+		"fakewebservices_load_balancer": {
+			id: "lb-1",
+			want: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "lb"),
+				"servers": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "web"),
+				}),
+			},
+		},
+		"fakewebservices_database": {
+			id: "db-1",
+			want: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "db"),
+				"size": tftypes.NewValue(tftypes.Number, 20),
+				"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+					"env": tftypes.NewValue(tftypes.String, "prod"),
+				}),
+			},
+		},
+	}
+
+	for typeName, tc := range cases {
+		t.Run(typeName, func(t *testing.T) {
+			s.t = t
+			ctx := context.Background()
+			schema := s.schema.ResourceSchemas[typeName]
+
+			imported, err := s.ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+				TypeName: typeName,
+				ID:       tc.id,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.check(imported.Diagnostics)
+			if len(imported.ImportedResources) != 1 {
+				t.Fatalf("got %d imported resources, want 1", len(imported.ImportedResources))
+			}
+
+			read, err := s.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+				TypeName:     typeName,
+				CurrentState: imported.ImportedResources[0].State,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.check(read.Diagnostics)
+
+			state := s.attributes(schema, read.NewState)
+			if !state["id"].Equal(tftypes.NewValue(tftypes.String, tc.id)) {
+				t.Errorf("got id %v, want %q", state["id"], tc.id)
+			}
+			for name, want := range tc.want {
+				if !state[name].Equal(want) {
+					t.Errorf("got %s %v, want %v", name, state[name], want)
+				}
+			}
+		})
+	}
+}
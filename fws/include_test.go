@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+// includeHandler serves body for path when it is read with include, and
+// the same object without its included objects otherwise.
+func includeHandler(t *testing.T, path, include, body, plain string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/fake-resources/"+path {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch got := r.URL.Query().Get("include"); got {
+		case include:
+			w.Write([]byte(body))
+		case "":
+			w.Write([]byte(plain))
+		default:
+			t.Errorf("got include %q, want %q", got, include)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+}
+
+func TestServer_includeVpc(t *testing.T) {
+	c := newTestClient(t, includeHandler(t, "servers/srv-1", ServerIncludeVpc, `{
+		"data": {
+			"type": "fake-resources-servers", "id": "srv-1",
+			"attributes": {"name": "web", "server-type": "t2.micro", "vpc": "main"},
+			"relationships": {"parent-vpc": {"data": {"type": "fake-resources-vpcs", "id": "vpc-1"}}}
+		},
+		"included": [
+			{"type": "fake-resources-vpcs", "id": "vpc-1", "attributes": {"name": "main", "cidr_block": "10.0.0.0/16"}}
+		]
+	}`, `{
+		"data": {
+			"type": "fake-resources-servers", "id": "srv-1",
+			"attributes": {"name": "web", "server-type": "t2.micro", "vpc": "main"}
+		}
+	}`))
+	ctx := context.Background()
+
+	read := func(opts interface{}) *Server {
+		t.Helper()
+
+		req, err := c.NewRequest("GET", "servers/srv-1", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := &Server{}
+		if err := c.Do(ctx, req, server); err != nil {
+			t.Fatal(err)
+		}
+		return server
+	}
+
+	server := read(client.ReadOptions{Include: []string{ServerIncludeVpc}})
+	if server.Name != "web" {
+		t.Errorf("got server %+v", server)
+	}
+	if vpc := server.ParentVpc; vpc == nil || vpc.ID != "vpc-1" || vpc.Name != "main" || vpc.CidrBlock != "10.0.0.0/16" {
+		t.Errorf("got VPC %+v, want the included vpc-1", vpc)
+	}
+
+	if server := read(nil); server.ParentVpc != nil {
+		t.Errorf("got VPC %+v without including it", server.ParentVpc)
+	}
+}
+
+func TestLoadBalancer_includeServers(t *testing.T) {
+	c := newTestClient(t, includeHandler(t, "load_balancers/lb-1", LoadBalancerIncludeServers, `{
+		"data": {
+			"type": "fake-resources-load-balancers", "id": "lb-1",
+			"attributes": {"name": "lb", "servers": ["web-1", "web-2"]},
+			"relationships": {"attached-servers": {"data": [
+				{"type": "fake-resources-servers", "id": "srv-1"},
+				{"type": "fake-resources-servers", "id": "srv-2"}
+			]}}
+		},
+		"included": [
+			{"type": "fake-resources-servers", "id": "srv-1", "attributes": {"name": "web-1", "server-type": "t2.micro"}},
+			{"type": "fake-resources-servers", "id": "srv-2", "attributes": {"name": "web-2", "server-type": "t2.small"}}
+		]
+	}`, `{
+		"data": {
+			"type": "fake-resources-load-balancers", "id": "lb-1",
+			"attributes": {"name": "lb", "servers": ["web-1", "web-2"]}
+		}
+	}`))
+
+	req, err := c.NewRequest("GET", "load_balancers/lb-1", client.ReadOptions{Include: []string{LoadBalancerIncludeServers}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lb := &LoadBalancer{}
+	if err := c.Do(context.Background(), req, lb); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lb.AttachedServers) != 2 {
+		t.Fatalf("got servers %+v, want the two included servers", lb.AttachedServers)
+	}
+	for i, want := range []Server{{ID: "srv-1", Name: "web-1", Type: "t2.micro"}, {ID: "srv-2", Name: "web-2", Type: "t2.small"}} {
+		if got := lb.AttachedServers[i]; got.ID != want.ID || got.Name != want.Name || got.Type != want.Type {
+			t.Errorf("got server %+v, want %+v", got, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+// All prices are in US dollars.
+const (
+	// hoursPerMonth is the number of hours used to turn hourly prices into
+	// monthly ones.
+	hoursPerMonth = 730
+
+	// databasePricePerGBMonth is the monthly price of one gigabyte of
+	// allocated database storage.
+	databasePricePerGBMonth = 0.115
+
+	// loadBalancerPricePerHour is the hourly price of a load balancer.
+	loadBalancerPricePerHour = 0.0225
+)
+
+// serverMonthlyCost returns the monthly cost of a server of the given type,
+// and whether the type has a price in the server type catalog.
+func serverMonthlyCost(serverType string) (float64, bool) {
+	t, ok := lookupServerType(serverType)
+	if !ok {
+		return 0, false
+	}
+	return t.PricePerHour * hoursPerMonth, true
+}
+
+// databaseMonthlyCost returns the monthly cost of a database of the given
+// size in gigabytes.
+func databaseMonthlyCost(size int) float64 {
+	return float64(size) * databasePricePerGBMonth
+}
+
+// loadBalancerMonthlyCost returns the monthly cost of a load balancer.
+func loadBalancerMonthlyCost() float64 {
+	return loadBalancerPricePerHour * hoursPerMonth
+}
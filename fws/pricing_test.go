@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"math"
+	"testing"
+)
+
+// closeTo reports whether two costs are equal to within a thousandth of a
+// cent.
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 0.00001
+}
+
+func TestServerMonthlyCost(t *testing.T) {
+	cases := map[string]struct {
+		want   float64
+		wantOK bool
+	}{
+		"t2.nano":    {want: 4.234, wantOK: true},
+		"t2.micro":   {want: 8.468, wantOK: true},
+		"m5.4xlarge": {want: 560.64, wantOK: true},
+		"x1.huge":    {want: 0, wantOK: false},
+	}
+
+	for serverType, tc := range cases {
+		t.Run(serverType, func(t *testing.T) {
+			got, ok := serverMonthlyCost(serverType)
+			if ok != tc.wantOK || !closeTo(got, tc.want) {
+				t.Errorf("got %v, %t, want %v, %t", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestDatabaseMonthlyCost(t *testing.T) {
+	for size, want := range map[int]float64{1: 0.115, 64: 7.36, 1000: 115} {
+		if got := databaseMonthlyCost(size); !closeTo(got, want) {
+			t.Errorf("got %v for %d GB, want %v", got, size, want)
+		}
+	}
+}
+
+func TestLoadBalancerMonthlyCost(t *testing.T) {
+	if got := loadBalancerMonthlyCost(); !closeTo(got, 16.425) {
+		t.Errorf("got %v, want 16.425", got)
+	}
+}
@@ -4,14 +4,19 @@
 package fws
 
 import (
+	"os"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-fakewebservices/client"
 )
 
-// TODO: ADD DOCUMENTATION
-// Provider -
-func Provider() *schema.Provider {
-	return &schema.Provider{
+// Provider returns the terraform-plugin-sdk provider. All resources have
+// moved to the framework provider returned by New; this one is only served
+// through the mux until the migration is complete, and its schema must stay
+// identical to the framework provider's.
+func Provider(version string) *schema.Provider {
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"hostname": {
 				Type:     schema.TypeString,
@@ -20,31 +25,152 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("FWS_HOSTNAME", client.DefaultHostname),
 			},
 			"token": {
-				Type:     schema.TypeString,
-				Optional: true,
-				DefaultFunc: func() (interface{}, error) {
-					// TODO: MAKE THIS WORK WITH DIFFERENT HOST
-					// TODO: MAKE THIS WORK WITH OTHER CRED TYPES (THE REMOTE ONES)
-					// TODO: PROBABLY REUSE terraform-svchost
-
-					creds := *cliCredentials()
-					return creds.Credentials["app.terraform.io"].Token, nil
-				},
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: defaultToken,
+			},
+			"ca_cert_file": {
+				Description: descCACertFile,
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"insecure_skip_verify": {
+				Description: descInsecureSkipVerify,
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"client_cert": {
+				Description: descClientCert,
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"client_key": {
+				Description: descClientKey,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"user_agent_suffix": {
+				Description: descUserAgentSuffix,
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"max_concurrent_requests": {
+				Description: descMaxConcurrentRequests,
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"requests_per_second": {
+				Description: descRequestsPerSecond,
+				Type:        schema.TypeFloat,
+				Optional:    true,
+			},
+			"batch_creates": {
+				Description: descBatchCreates,
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"read_after_create_timeout": {
+				Description: descReadAfterCreateTimeout,
+				Type:        schema.TypeInt,
+				Optional:    true,
 			},
 		},
-		ResourcesMap: map[string]*schema.Resource{
-			"fakewebservices_server":        resourceFWSServer(),
-			"fakewebservices_database":      resourceFWSDatabase(),
-			"fakewebservices_load_balancer": resourceFWSLoadBalancer(),
-			"fakewebservices_vpc":           resourceFWSVpc(),
-		},
+		ResourcesMap:   map[string]*schema.Resource{},
 		DataSourcesMap: map[string]*schema.Resource{},
-		ConfigureFunc:  providerConfigure,
 	}
+
+	// The framework provider builds the client and reports configuration
+	// errors, so doing either here would only report them twice.
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return nil, nil
+	}
+
+	return p
+}
+
+// Descriptions of the provider attributes, shared by both providers so
+// their schemas stay identical.
+const (
+	descCACertFile         = "Path to a PEM-encoded CA bundle used to verify the API's certificate, in addition to the system roots."
+	descInsecureSkipVerify = "Skip verification of the API's TLS certificate. Only use this for testing."
+	descClientCert         = "PEM-encoded client certificate to present to the API for mutual TLS. Requires client_key."
+	descClientKey          = "PEM-encoded private key for client_cert."
+	descUserAgentSuffix    = "Text appended to the User-Agent header sent with every API request."
+
+	descMaxConcurrentRequests  = "The maximum number of API requests in flight at once, shared by all resources. Unlimited by default."
+	descRequestsPerSecond      = "The maximum number of API requests sent per second, retries included. Unlimited by default."
+	descBatchCreates           = "Whether to send servers created at the same time, such as with `count`, as a single bulk API request. Defaults to `false`."
+	descReadAfterCreateTimeout = "How long, in seconds, to wait for a newly created object to become visible in the API before failing. Defaults to 30; 0 disables waiting."
+)
+
+// clientConfig holds the provider configuration needed to build a client.
+type clientConfig struct {
+	Hostname string
+	Token    string
+	TLS      client.TLSOptions
+
+	ProviderVersion  string
+	TerraformVersion string
+	UserAgentSuffix  string
+
+	MaxConcurrentRequests int
+	RequestsPerSecond     float64
+	BatchCreates          bool
+
+	// ReadAfterCreateTimeout is in seconds; nil means the client default.
+	ReadAfterCreateTimeout *int
+}
+
+// createBatchWindow is how long a create waits for others to share its bulk
+// request when batch_creates is set.
+const createBatchWindow = 50 * time.Millisecond
+
+func (c clientConfig) newClient() (*client.Client, error) {
+	tlsConfig, err := client.NewTLSConfig(c.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []client.Option{
+		client.WithUserAgent(client.UserAgent(c.ProviderVersion, c.TerraformVersion, c.UserAgentSuffix)),
+		client.WithMaxConcurrentRequests(c.MaxConcurrentRequests),
+		client.WithRequestsPerSecond(c.RequestsPerSecond),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, client.WithTLSConfig(tlsConfig))
+	}
+	if c.BatchCreates {
+		opts = append(opts, client.WithBatchWindow(createBatchWindow))
+	}
+	if c.ReadAfterCreateTimeout != nil {
+		opts = append(opts, client.WithReadAfterCreateTimeout(time.Duration(*c.ReadAfterCreateTimeout)*time.Second))
+	}
+
+	return client.NewClient(c.Hostname, c.Token, opts...)
+}
+
+// DefaultHostname returns the hostname the provider uses when none is
+// configured: FWS_HOSTNAME if set, otherwise client.DefaultHostname.
+func DefaultHostname() string {
+	if hostname := os.Getenv("FWS_HOSTNAME"); hostname != "" {
+		return hostname
+	}
+	return client.DefaultHostname
+}
+
+// DefaultToken returns the token the provider uses when none is
+// configured, read from the Terraform CLI credentials file.
+func DefaultToken() string {
+	// TODO: MAKE THIS WORK WITH DIFFERENT HOST
+	// TODO: MAKE THIS WORK WITH OTHER CRED TYPES (THE REMOTE ONES)
+	// TODO: PROBABLY REUSE terraform-svchost
+
+	creds := *cliCredentials()
+	return creds.Credentials["app.terraform.io"].Token
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	hostname := d.Get("hostname").(string)
-	token := d.Get("token").(string)
-	return client.NewClient(hostname, token)
+// defaultToken is DefaultToken as a schema.SchemaDefaultFunc.
+func defaultToken() (interface{}, error) {
+	return DefaultToken(), nil
 }
@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ provider.Provider                       = &fwsProvider{}
+	_ provider.ProviderWithFunctions          = &fwsProvider{}
+	_ provider.ProviderWithEphemeralResources = &fwsProvider{}
+)
+
+type fwsProvider struct {
+	version string
+}
+
+type fwsProviderModel struct {
+	Hostname           types.String `tfsdk:"hostname"`
+	Token              types.String `tfsdk:"token"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	UserAgentSuffix    types.String `tfsdk:"user_agent_suffix"`
+
+	MaxConcurrentRequests  types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond      types.Float64 `tfsdk:"requests_per_second"`
+	BatchCreates           types.Bool    `tfsdk:"batch_creates"`
+	ReadAfterCreateTimeout types.Int64   `tfsdk:"read_after_create_timeout"`
+}
+
+// New returns a constructor for the terraform-plugin-framework provider.
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &fwsProvider{
+			version: version,
+		}
+	}
+}
+
+func (p *fwsProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "fakewebservices"
+	resp.Version = p.version
+}
+
+func (p *fwsProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"hostname": schema.StringAttribute{
+				Optional: true,
+			},
+			"token": schema.StringAttribute{
+				Optional: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: descCACertFile,
+				Optional:    true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: descInsecureSkipVerify,
+				Optional:    true,
+			},
+			"client_cert": schema.StringAttribute{
+				Description: descClientCert,
+				Optional:    true,
+			},
+			"client_key": schema.StringAttribute{
+				Description: descClientKey,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"user_agent_suffix": schema.StringAttribute{
+				Description: descUserAgentSuffix,
+				Optional:    true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: descMaxConcurrentRequests,
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: descRequestsPerSecond,
+				Optional:    true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.1),
+				},
+			},
+			"batch_creates": schema.BoolAttribute{
+				Description: descBatchCreates,
+				Optional:    true,
+			},
+			"read_after_create_timeout": schema.Int64Attribute{
+				Description: descReadAfterCreateTimeout,
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+		},
+	}
+}
+
+func (p *fwsProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config fwsProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Hostname.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hostname"),
+			"Unknown FWS hostname",
+			"The provider cannot create the FWS client as there is an unknown configuration value for the hostname.",
+		)
+	}
+	if config.Token.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Unknown FWS token",
+			"The provider cannot create the FWS client as there is an unknown configuration value for the token.",
+		)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hostname := config.Hostname.ValueString()
+	if hostname == "" {
+		hostname = DefaultHostname()
+	}
+
+	token := config.Token.ValueString()
+	if token == "" {
+		token = DefaultToken()
+	}
+
+	cc := clientConfig{
+		Hostname:         hostname,
+		Token:            token,
+		ProviderVersion:  p.version,
+		TerraformVersion: req.TerraformVersion,
+		UserAgentSuffix:  config.UserAgentSuffix.ValueString(),
+
+		MaxConcurrentRequests: int(config.MaxConcurrentRequests.ValueInt64()),
+		RequestsPerSecond:     config.RequestsPerSecond.ValueFloat64(),
+		BatchCreates:          config.BatchCreates.ValueBool(),
+		TLS: client.TLSOptions{
+			CACertFile:         config.CACertFile.ValueString(),
+			InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+			ClientCert:         config.ClientCert.ValueString(),
+			ClientKey:          config.ClientKey.ValueString(),
+		},
+	}
+	if !config.ReadAfterCreateTimeout.IsNull() {
+		timeout := int(config.ReadAfterCreateTimeout.ValueInt64())
+		cc.ReadAfterCreateTimeout = &timeout
+	}
+
+	fwsClient, err := cc.newClient()
+	if err != nil {
+		resp.Diagnostics.AddError("Error configuring the FWS client", err.Error())
+		return
+	}
+
+	resp.DataSourceData = fwsClient
+	resp.EphemeralResourceData = fwsClient
+	resp.ResourceData = fwsClient
+}
+
+func (p *fwsProvider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewServerResource,
+		NewDatabaseResource,
+		NewLoadBalancerResource,
+		NewVpcResource,
+	}
+}
+
+func (p *fwsProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		NewCostEstimateDataSource,
+		NewServerTypesDataSource,
+	}
+}
+
+func (p *fwsProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewDatabaseCredentialsEphemeralResource,
+	}
+}
+
+func (p *fwsProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewCidrContainsFunction,
+		NewSubnetPlanFunction,
+		NewServerTypeSpecFunction,
+	}
+}
+
+// optionalString maps the API's empty string for an unset optional
+// attribute to a null value, so it matches a configuration that omits it.
+func optionalString(v string) types.String {
+	if v == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(v)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestProvider_configureIsNoop(t *testing.T) {
+	p := Provider("test")
+
+	// An invalid configuration is left for the framework provider to
+	// report.
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"token":       "test-token",
+		"client_cert": "not a certificate",
+	}))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if p.Meta() != nil {
+		t.Errorf("got meta %v, want nil", p.Meta())
+	}
+}
+
+// The mux requires both providers to have the same schema.
+func TestProvider_schemaMatchesFramework(t *testing.T) {
+	resp := &provider.SchemaResponse{}
+	New("test")().Schema(context.Background(), provider.SchemaRequest{}, resp)
+
+	var framework, sdk []string
+	for name := range resp.Schema.Attributes {
+		framework = append(framework, name)
+	}
+	for name := range Provider("test").Schema {
+		sdk = append(sdk, name)
+	}
+	slices.Sort(framework)
+	slices.Sort(sdk)
+
+	if !slices.Equal(sdk, framework) {
+		t.Errorf("SDK provider attributes %v differ from the framework provider's %v", sdk, framework)
+	}
+}
+
+// testProviderServer is the framework provider, configured for the API
+// served by a test's handler and driven through the plugin protocol.
+type testProviderServer struct {
+	t *testing.T
+	tfprotov5.ProviderServer
+	schema *tfprotov5.GetProviderSchemaResponse
+}
+
+func newTestProviderServer(t *testing.T, handler http.Handler) *testProviderServer {
+	t.Helper()
+	ctx := context.Background()
+
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caCertFile, caCert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testProviderServer{t: t, ProviderServer: providerserver.NewProtocol5(New("test")())()}
+	schema, err := s.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.schema = schema
+
+	resp, err := s.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: "1.11.0",
+		Config: s.value(schema.Provider, map[string]tftypes.Value{
+			"hostname":     tftypes.NewValue(tftypes.String, strings.TrimPrefix(srv.URL, "https://")),
+			"token":        tftypes.NewValue(tftypes.String, "test-token"),
+			"ca_cert_file": tftypes.NewValue(tftypes.String, caCertFile),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.check(resp.Diagnostics)
+
+	return s
+}
+
+// value returns an object of schema's type holding attrs, with every other
+// attribute null.
+func (s *testProviderServer) value(schema *tfprotov5.Schema, attrs map[string]tftypes.Value) *tfprotov5.DynamicValue {
+	s.t.Helper()
+
+	typ := schema.ValueType().(tftypes.Object)
+	vals := map[string]tftypes.Value{}
+	for name, attrType := range typ.AttributeTypes {
+		vals[name] = tftypes.NewValue(attrType, nil)
+		if v, ok := attrs[name]; ok {
+			vals[name] = v
+		}
+	}
+
+	dv, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, vals))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return &dv
+}
+
+// attributes decodes dv, an object of schema's type, into its attributes.
+func (s *testProviderServer) attributes(schema *tfprotov5.Schema, dv *tfprotov5.DynamicValue) map[string]tftypes.Value {
+	s.t.Helper()
+
+	v, err := dv.Unmarshal(schema.ValueType())
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	var attrs map[string]tftypes.Value
+	if err := v.As(&attrs); err != nil {
+		s.t.Fatal(err)
+	}
+	return attrs
+}
+
+// check fails the test on error diagnostics.
+func (s *testProviderServer) check(diags []*tfprotov5.Diagnostic) {
+	s.t.Helper()
+	for _, d := range diags {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			s.t.Fatalf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+}
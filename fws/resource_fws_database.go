@@ -4,151 +4,332 @@
 package fws
 
 import (
+	"context"
 	"fmt"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-provider-fakewebservices/client"
 )
 
-func resourceFWSDatabase() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceFWSDatabaseCreate,
-		Read:   resourceFWSDatabaseRead,
-		Update: resourceFWSDatabaseUpdate,
-		Delete: resourceFWSDatabaseDelete,
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &databaseResource{}
+	_ resource.ResourceWithConfigure   = &databaseResource{}
+	_ resource.ResourceWithImportState = &databaseResource{}
+)
+
+type databaseResource struct {
+	client *client.Client
+}
+
+type databaseResourceModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Size types.Int64  `tfsdk:"size"`
+	Tags types.Map    `tfsdk:"tags"`
+
+	Password        types.String `tfsdk:"password"`
+	PasswordVersion types.Int64  `tfsdk:"password_version"`
+}
+
+// NewDatabaseResource is a helper function to simplify the provider implementation.
+func NewDatabaseResource() resource.Resource {
+	return &databaseResource{}
+}
+
+func (r *databaseResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database"
+}
 
-		Schema: map[string]*schema.Schema{
-			"name": {
+func (r *databaseResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of this resource.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
 				Description: "The name of the database.",
-				Type:        schema.TypeString,
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"size": {
+			"size": schema.Int64Attribute{
 				Description: "The allocated size of the database in gigabytes.",
-				Type:        schema.TypeInt,
 				Required:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"tags": tagsAttribute("database"),
+			"password": schema.StringAttribute{
+				Description: "The password of the database's admin user. This value is write-only: it is sent to the API " +
+					"but never stored in plan or state. Requires Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("password_version")),
+				},
+			},
+			"password_version": schema.Int64Attribute{
+				Description: "The version of password. Change this to send a new password to the API on update.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("password")),
+				},
 			},
 		},
 	}
 }
 
-func resourceFWSDatabaseCreate(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *databaseResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
 
-	name := d.Get("name").(string)
-	size := d.Get("size").(int)
+	fwsClient, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+		)
+		return
+	}
 
-	options := DatabaseCreateOptions{
-		Name: client.String(name),
-		Size: client.Int(size),
+	r.client = fwsClient
+}
+
+func (r *databaseResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan databaseResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	req, err := fwsClient.NewRequest("POST", "databases", &options)
+	// Write-only values are only available from the configuration.
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	tags, diags := expandTags(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	options := DatabaseCreateOptions{
+		Name: client.String(plan.Name.ValueString()),
+		Size: client.Int(int(plan.Size.ValueInt64())),
+		Tags: tags,
+	}
+	if !password.IsNull() {
+		options.Password = client.String(password.ValueString())
+	}
+
+	fwsReq, err := r.client.NewRequest("POST", "databases", &options)
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error creating database", err.Error())
+		return
 	}
 
 	database := &Database{}
 
-	log.Printf("[DEBUG] Creating new database with name: %s", name)
-	err = fwsClient.Do(req, database)
+	log.Printf("[DEBUG] Creating new database with name: %s", plan.Name.ValueString())
+	err = r.client.Do(ctx, fwsReq, database)
 	if err != nil {
-		return fmt.Errorf("Error creating database: %v", err)
+		resp.Diagnostics.AddError("Error creating database", err.Error())
+		return
 	}
 
-	d.SetId(database.ID)
+	plan.ID = types.StringValue(database.ID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	return resourceFWSDatabaseRead(d, meta)
+	r.read(ctx, &plan, &resp.State, &resp.Diagnostics, true)
 }
 
-func resourceFWSDatabaseRead(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *databaseResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state databaseResourceModel
 
-	req, err := fwsClient.NewRequest("GET", fmt.Sprintf("databases/%s", d.Id()), nil)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(ctx, &state, &resp.State, &resp.Diagnostics, false)
+}
+
+// read refreshes the given model from the API and stores it in state,
+// removing the resource from state if it no longer exists.
+func (r *databaseResource) read(ctx context.Context, m *databaseResourceModel, state *tfsdk.State, diags *diag.Diagnostics, afterCreate bool) {
+	id := m.ID.ValueString()
 
+	req, err := r.client.NewRequest("GET", fmt.Sprintf("databases/%s", id), nil)
 	if err != nil {
-		return err
+		diags.AddError("Error reading database", err.Error())
+		return
 	}
 
 	database := &Database{}
 
-	log.Printf("[DEBUG] Reading database: %s", d.Id())
-	err = fwsClient.Do(req, database)
+	log.Printf("[DEBUG] Reading database: %s", id)
+	if afterCreate {
+		err = r.client.DoAfterCreate(ctx, req, database)
+	} else {
+		err = r.client.Do(ctx, req, database)
+	}
 	if err != nil {
-		if err == client.ErrResourceNotFound {
-			log.Printf("[DEBUG] database %s no longer exists", d.Id())
-			d.SetId("")
-			return nil
+		// A new object that never became visible is an error, not an
+		// object that has since been deleted.
+		if err == client.ErrResourceNotFound && !afterCreate {
+			log.Printf("[DEBUG] database %s no longer exists", id)
+			state.RemoveResource(ctx)
+			return
 		}
-		return fmt.Errorf("Error reading configuration of database %s: %v", d.Id(), err)
+		diags.AddError(
+			"Error reading database",
+			fmt.Sprintf("Error reading configuration of database %s: %v", id, err),
+		)
+		return
 	}
 
 	// Update the config.
-	d.Set("name", database.Name)
+	m.Name = types.StringValue(database.Name)
+
+	// The size is only read on import; otherwise it comes from the
+	// configuration.
+	if m.Size.IsNull() {
+		m.Size = types.Int64Value(int64(database.Size))
+	}
 
-	return nil
+	tags, d := flattenTags(ctx, m.Tags, database.Tags)
+	diags.Append(d...)
+	m.Tags = tags
+
+	diags.Append(state.Set(ctx, m)...)
 }
 
-func resourceFWSDatabaseUpdate(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *databaseResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state databaseResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	name := d.Get("name").(string)
-	size := d.Get("size").(int)
+	tags, diags := expandTags(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	options := DatabaseUpdateOptions{
-		Name: client.String(name),
-		Size: client.Int(size),
+		Name: client.String(plan.Name.ValueString()),
+		Size: client.Int(int(plan.Size.ValueInt64())),
+		Tags: tags,
 	}
 
-	req, err := fwsClient.NewRequest(
+	// The password can't be diffed, so it is only sent when its version
+	// changes.
+	if !plan.PasswordVersion.Equal(state.PasswordVersion) {
+		var password types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !password.IsNull() {
+			options.Password = client.String(password.ValueString())
+		}
+	}
+
+	fwsReq, err := r.client.NewRequest(
 		"PATCH",
-		fmt.Sprintf("databases/%s", d.Id()),
+		fmt.Sprintf("databases/%s", plan.ID.ValueString()),
 		&options,
 	)
-
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error updating database", err.Error())
+		return
 	}
 
 	database := &Database{}
 
-	log.Printf("[DEBUG] Updating database: %s", d.Id())
-	err = fwsClient.Do(req, database)
+	log.Printf("[DEBUG] Updating database: %s", plan.ID.ValueString())
+	err = r.client.Do(ctx, fwsReq, database)
 	if err != nil {
-		return fmt.Errorf("Error updating database: %v", err)
+		resp.Diagnostics.AddError("Error updating database", err.Error())
+		return
 	}
 
-	return resourceFWSDatabaseRead(d, meta)
+	r.read(ctx, &plan, &resp.State, &resp.Diagnostics, false)
 }
 
-func resourceFWSDatabaseDelete(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *databaseResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state databaseResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	req, err := fwsClient.NewRequest(
+	fwsReq, err := r.client.NewRequest(
 		"DELETE",
-		fmt.Sprintf("databases/%s", d.Id()),
+		fmt.Sprintf("databases/%s", state.ID.ValueString()),
 		nil,
 	)
-
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error destroying database", err.Error())
+		return
 	}
 
-	log.Printf("[DEBUG] Destroying database: %s", d.Id())
-	err = fwsClient.Do(req, nil)
+	log.Printf("[DEBUG] Destroying database: %s", state.ID.ValueString())
+	err = r.client.Do(ctx, fwsReq, nil)
 	if err != nil {
-		return fmt.Errorf("Error destroying database: %v", err)
+		resp.Diagnostics.AddError("Error destroying database", err.Error())
+		return
 	}
+}
 
-	return nil
+func (r *databaseResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
 type Database struct {
 	ID   string `jsonapi:"primary,fake-resources-databases"`
 	Name string `jsonapi:"attr,name,omitempty"`
 	Size int    `jsonapi:"attr,size,omitempty"`
+
+	Tags map[string]interface{} `jsonapi:"attr,tags,omitempty"`
+}
+
+// DatabaseList represents a page of databases.
+type DatabaseList struct {
+	*client.Pagination
+	Items []*Database
 }
 
 type DatabaseCreateOptions struct {
@@ -159,6 +340,11 @@ type DatabaseCreateOptions struct {
 	Name *string `jsonapi:"attr,name"`
 
 	Size *int `jsonapi:"attr,size"`
+
+	Tags *map[string]string `jsonapi:"attr,tags"`
+
+	// The admin password, only sent when set.
+	Password *string `jsonapi:"attr,password,omitempty"`
 }
 
 type DatabaseUpdateOptions struct {
@@ -169,4 +355,9 @@ type DatabaseUpdateOptions struct {
 	Name *string `jsonapi:"attr,name"`
 
 	Size *int `jsonapi:"attr,size"`
+
+	Tags *map[string]string `jsonapi:"attr,tags"`
+
+	// The admin password, only sent when set.
+	Password *string `jsonapi:"attr,password,omitempty"`
 }
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.ResourceWithUpgradeState = &databaseResource{}
+
+// databaseResourceModelV0 is the state written by the terraform-plugin-sdk
+// implementation of the resource.
+type databaseResourceModelV0 struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Size types.Int64  `tfsdk:"size"`
+}
+
+func (r *databaseResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"name": schema.StringAttribute{
+						Required: true,
+					},
+					"size": schema.Int64Attribute{
+						Required: true,
+					},
+				},
+			},
+			StateUpgrader: upgradeDatabaseStateV0,
+		},
+	}
+}
+
+// upgradeDatabaseStateV0 carries SDK state over to version 1 unchanged.
+func upgradeDatabaseStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior databaseResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := databaseResourceModel{
+		ID:   prior.ID,
+		Name: prior.Name,
+		Size: prior.Size,
+		Tags: types.MapNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDatabaseUpgradeStateV0(t *testing.T) {
+	state := upgradeStateV0(t, &databaseResource{}, `{"id": "db-1", "name": "prod", "size": 256}`)
+
+	var got databaseResourceModel
+	if diags := state.Get(context.Background(), &got); diags.HasError() {
+		t.Fatalf("error reading upgraded state: %v", diags)
+	}
+
+	// The write-only password arguments and tags did not exist in
+	// version 0.
+	want := databaseResourceModel{
+		ID:              types.StringValue("db-1"),
+		Name:            types.StringValue("prod"),
+		Size:            types.Int64Value(256),
+		Tags:            types.MapNull(types.StringType),
+		Password:        types.StringNull(),
+		PasswordVersion: types.Int64Null(),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
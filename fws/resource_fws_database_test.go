@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const testDatabase = `{"data": {"type": "fake-resources-databases", "id": "db-1", "attributes": {"name": "db", "size": 10}}}`
+
+// The password is write-only: it is sent to the API from the
+// configuration, but never stored, and only resent when its version
+// changes.
+func TestDatabaseResource_writeOnlyPassword(t *testing.T) {
+	api := &fakeAPI{responses: map[string]apiResponse{
+		"POST databases":       {http.StatusCreated, testDatabase},
+		"GET databases/db-1":   {http.StatusOK, testDatabase},
+		"PATCH databases/db-1": {http.StatusOK, testDatabase},
+	}}
+	s := newTestProviderServer(t, api)
+	ctx := context.Background()
+	schema := s.schema.ResourceSchemas["fakewebservices_database"]
+
+	database := func(id interface{}, password interface{}, version int) *tfprotov5.DynamicValue {
+		return s.value(schema, map[string]tftypes.Value{
+			"id":               tftypes.NewValue(tftypes.String, id),
+			"name":             tftypes.NewValue(tftypes.String, "db"),
+			"size":             tftypes.NewValue(tftypes.Number, 10),
+			"password":         tftypes.NewValue(tftypes.String, password),
+			"password_version": tftypes.NewValue(tftypes.Number, version),
+		})
+	}
+	apply := func(prior *tfprotov5.DynamicValue, password string, version int) map[string]tftypes.Value {
+		t.Helper()
+
+		id := interface{}("db-1")
+		if prior == nil {
+			// A create has no prior state.
+			null, err := tfprotov5.NewDynamicValue(schema.ValueType(), tftypes.NewValue(schema.ValueType(), nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			prior = &null
+			id = tftypes.UnknownValue
+		}
+		resp, err := s.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+			TypeName:     "fakewebservices_database",
+			PriorState:   prior,
+			PlannedState: database(id, nil, version),
+			Config:       database(nil, password, version),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.check(resp.Diagnostics)
+
+		state := s.attributes(schema, resp.NewState)
+		if !state["password"].IsNull() {
+			t.Fatalf("got password %v in state", state["password"])
+		}
+		return state
+	}
+	sentPassword := func(method string) interface{} {
+		t.Helper()
+
+		reqs := api.received(method)
+		if len(reqs) == 0 {
+			t.Fatalf("got no %s requests", method)
+		}
+		return reqs[len(reqs)-1].attributes["password"]
+	}
+
+	apply(nil, "first", 1)
+	if got := sentPassword("POST"); got != "first" {
+		t.Errorf("created with password %v, want first", got)
+	}
+
+	prior := database("db-1", nil, 1)
+	apply(prior, "ignored", 1)
+	if got := sentPassword("PATCH"); got != nil {
+		t.Errorf("updated with password %v, want none while its version is unchanged", got)
+	}
+
+	apply(prior, "second", 2)
+	if got := sentPassword("PATCH"); got != "second" {
+		t.Errorf("updated with password %v, want second", got)
+	}
+}
@@ -4,167 +4,327 @@
 package fws
 
 import (
+	"context"
 	"fmt"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-provider-fakewebservices/client"
 )
 
-func resourceFWSLoadBalancer() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceFWSLoadBalancerCreate,
-		Read:   resourceFWSLoadBalancerRead,
-		Update: resourceFWSLoadBalancerUpdate,
-		Delete: resourceFWSLoadBalancerDelete,
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &loadBalancerResource{}
+	_ resource.ResourceWithConfigure   = &loadBalancerResource{}
+	_ resource.ResourceWithImportState = &loadBalancerResource{}
+)
+
+type loadBalancerResource struct {
+	client *client.Client
+}
+
+type loadBalancerResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Servers types.Set    `tfsdk:"servers"`
+	Tags    types.Map    `tfsdk:"tags"`
+}
+
+// NewLoadBalancerResource is a helper function to simplify the provider implementation.
+func NewLoadBalancerResource() resource.Resource {
+	return &loadBalancerResource{}
+}
+
+func (r *loadBalancerResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_load_balancer"
+}
 
-		Schema: map[string]*schema.Schema{
-			"name": {
+func (r *loadBalancerResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of this resource.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
 				Description: "The name of the load balancer.",
-				Type:        schema.TypeString,
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"servers": {
+			"servers": schema.SetAttribute{
 				Description: "A list of server names to attach to the load balancer.",
-				Type:        schema.TypeSet,
+				ElementType: types.StringType,
 				Optional:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Set:         schema.HashString,
 			},
+			"tags": tagsAttribute("load balancer"),
 		},
 	}
 }
 
-func resourceFWSLoadBalancerCreate(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *loadBalancerResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	fwsClient, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = fwsClient
+}
+
+func (r *loadBalancerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan loadBalancerResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	servers := []string{}
+	if !plan.Servers.IsNull() {
+		resp.Diagnostics.Append(plan.Servers.ElementsAs(ctx, &servers, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
-	name := d.Get("name").(string)
-	servers := client.ExpandStringSet(d.Get("servers").(*schema.Set))
+	tags, diags := expandTags(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	options := LoadBalancerCreateOptions{
-		Name:    client.String(name),
+		Name:    client.String(plan.Name.ValueString()),
 		Servers: &servers,
+		Tags:    tags,
 	}
 
-	req, err := fwsClient.NewRequest("POST", "load_balancers", &options)
-
+	fwsReq, err := r.client.NewRequest("POST", "load_balancers", &options)
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error creating load_balancer", err.Error())
+		return
 	}
 
 	lb := &LoadBalancer{}
 
-	log.Printf("[DEBUG] Creating new load_balancer with name: %s", name)
-	err = fwsClient.Do(req, lb)
+	log.Printf("[DEBUG] Creating new load_balancer with name: %s", plan.Name.ValueString())
+	err = r.client.Do(ctx, fwsReq, lb)
 	if err != nil {
-		return fmt.Errorf("Error creating load_balancer: %v", err)
+		resp.Diagnostics.AddError("Error creating load_balancer", err.Error())
+		return
 	}
 
-	d.SetId(lb.ID)
+	plan.ID = types.StringValue(lb.ID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	return resourceFWSLoadBalancerRead(d, meta)
+	r.read(ctx, &plan, &resp.State, &resp.Diagnostics, true)
 }
 
-func resourceFWSLoadBalancerRead(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *loadBalancerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state loadBalancerResourceModel
 
-	req, err := fwsClient.NewRequest("GET", fmt.Sprintf("load_balancers/%s", d.Id()), nil)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(ctx, &state, &resp.State, &resp.Diagnostics, false)
+}
 
+// read refreshes the given model from the API and stores it in state,
+// removing the resource from state if it no longer exists.
+func (r *loadBalancerResource) read(ctx context.Context, m *loadBalancerResourceModel, state *tfsdk.State, diags *diag.Diagnostics, afterCreate bool) {
+	id := m.ID.ValueString()
+
+	req, err := r.client.NewRequest("GET", fmt.Sprintf("load_balancers/%s", id), nil)
 	if err != nil {
-		return err
+		diags.AddError("Error reading load_balancer", err.Error())
+		return
 	}
 
 	lb := &LoadBalancer{}
 
-	log.Printf("[DEBUG] Reading load_balancer: %s", d.Id())
-	err = fwsClient.Do(req, lb)
+	log.Printf("[DEBUG] Reading load_balancer: %s", id)
+	if afterCreate {
+		err = r.client.DoAfterCreate(ctx, req, lb)
+	} else {
+		err = r.client.Do(ctx, req, lb)
+	}
 	if err != nil {
-		if err == client.ErrResourceNotFound {
-			log.Printf("[DEBUG] load_balancer %s no longer exists", d.Id())
-			d.SetId("")
-			return nil
+		// A new object that never became visible is an error, not an
+		// object that has since been deleted.
+		if err == client.ErrResourceNotFound && !afterCreate {
+			log.Printf("[DEBUG] load_balancer %s no longer exists", id)
+			state.RemoveResource(ctx)
+			return
 		}
-		return fmt.Errorf("Error reading configuration of load_balancer %s: %v", d.Id(), err)
+		diags.AddError(
+			"Error reading load_balancer",
+			fmt.Sprintf("Error reading configuration of load_balancer %s: %v", id, err),
+		)
+		return
 	}
 
 	// Update the config.
-	d.Set("name", lb.Name)
+	m.Name = types.StringValue(lb.Name)
+
+	// Servers are only read when none are set, such as on import;
+	// otherwise they come from the configuration.
+	if m.Servers.IsNull() && len(lb.Servers) > 0 {
+		servers, d := types.SetValueFrom(ctx, types.StringType, lb.Servers)
+		diags.Append(d...)
+		m.Servers = servers
+	}
 
-	return nil
+	tags, d := flattenTags(ctx, m.Tags, lb.Tags)
+	diags.Append(d...)
+	m.Tags = tags
+
+	diags.Append(state.Set(ctx, m)...)
 }
 
-func resourceFWSLoadBalancerUpdate(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *loadBalancerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan loadBalancerResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	name := d.Get("name").(string)
-	servers := client.ExpandStringSet(d.Get("servers").(*schema.Set))
+	servers := []string{}
+	if !plan.Servers.IsNull() {
+		resp.Diagnostics.Append(plan.Servers.ElementsAs(ctx, &servers, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	tags, diags := expandTags(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	options := LoadBalancerUpdateOptions{
-		Name:    client.String(name),
+		Name:    client.String(plan.Name.ValueString()),
 		Servers: &servers,
+		Tags:    tags,
 	}
 
-	req, err := fwsClient.NewRequest(
+	fwsReq, err := r.client.NewRequest(
 		"PATCH",
-		fmt.Sprintf("load_balancers/%s", d.Id()),
+		fmt.Sprintf("load_balancers/%s", plan.ID.ValueString()),
 		&options,
 	)
-
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error updating load_balancer", err.Error())
+		return
 	}
 
 	lb := &LoadBalancer{}
 
-	log.Printf("[DEBUG] Updating load_balancer: %s", d.Id())
-	err = fwsClient.Do(req, lb)
+	log.Printf("[DEBUG] Updating load_balancer: %s", plan.ID.ValueString())
+	err = r.client.Do(ctx, fwsReq, lb)
 	if err != nil {
-		return fmt.Errorf("Error updating load_balancer: %v", err)
+		resp.Diagnostics.AddError("Error updating load_balancer", err.Error())
+		return
 	}
 
-	return resourceFWSLoadBalancerRead(d, meta)
+	r.read(ctx, &plan, &resp.State, &resp.Diagnostics, false)
 }
 
-func resourceFWSLoadBalancerDelete(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *loadBalancerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state loadBalancerResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	req, err := fwsClient.NewRequest(
+	fwsReq, err := r.client.NewRequest(
 		"DELETE",
-		fmt.Sprintf("load_balancers/%s", d.Id()),
+		fmt.Sprintf("load_balancers/%s", state.ID.ValueString()),
 		nil,
 	)
-
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error destroying load_balancer", err.Error())
+		return
 	}
 
-	log.Printf("[DEBUG] Destroying load_balancer: %s", d.Id())
-	err = fwsClient.Do(req, nil)
+	log.Printf("[DEBUG] Destroying load_balancer: %s", state.ID.ValueString())
+	err = r.client.Do(ctx, fwsReq, nil)
 	if err != nil {
-		return fmt.Errorf("Error destroying load_balancer: %v", err)
+		resp.Diagnostics.AddError("Error destroying load_balancer", err.Error())
+		return
 	}
+}
 
-	return nil
+func (r *loadBalancerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
 type LoadBalancer struct {
 	ID      string   `jsonapi:"primary,fake-resources-load-balancers"`
 	Name    string   `jsonapi:"attr,name,omitempty"`
 	Servers []string `jsonapi:"attr,servers,omitempty"`
+
+	Tags map[string]interface{} `jsonapi:"attr,tags,omitempty"`
+
+	// AttachedServers is only set when read with
+	// LoadBalancerIncludeServers.
+	AttachedServers []*Server `jsonapi:"relation,attached-servers,omitempty"`
+}
+
+// LoadBalancerIncludeServers includes a load balancer's servers when
+// reading it.
+const LoadBalancerIncludeServers = "attached-servers"
+
+// LoadBalancerList represents a page of load balancers.
+type LoadBalancerList struct {
+	*client.Pagination
+	Items []*LoadBalancer
 }
 
 type LoadBalancerCreateOptions struct {
 	// For internal use only!
 	ID string `jsonapi:"primary,fake-resources-load-balancers"`
 
-	Name    *string   `jsonapi:"attr,name"`
-	Servers *[]string `jsonapi:"attr,servers"`
+	Name    *string            `jsonapi:"attr,name"`
+	Servers *[]string          `jsonapi:"attr,servers"`
+	Tags    *map[string]string `jsonapi:"attr,tags"`
 }
 
 type LoadBalancerUpdateOptions struct {
 	// For internal use only!
 	ID string `jsonapi:"primary,fake-resources-load-balancers"`
 
-	Name    *string   `jsonapi:"attr,name"`
-	Servers *[]string `jsonapi:"attr,servers"`
+	Name    *string            `jsonapi:"attr,name"`
+	Servers *[]string          `jsonapi:"attr,servers"`
+	Tags    *map[string]string `jsonapi:"attr,tags"`
 }
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.ResourceWithUpgradeState = &loadBalancerResource{}
+
+// loadBalancerResourceModelV0 is the state written by the
+// terraform-plugin-sdk implementation of the resource.
+type loadBalancerResourceModelV0 struct {
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Servers types.Set    `tfsdk:"servers"`
+}
+
+func (r *loadBalancerResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"name": schema.StringAttribute{
+						Required: true,
+					},
+					"servers": schema.SetAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+			StateUpgrader: upgradeLoadBalancerStateV0,
+		},
+	}
+}
+
+// upgradeLoadBalancerStateV0 carries SDK state over to version 1. The SDK
+// could store an unset servers attribute as an empty set, which is now
+// represented as null.
+func upgradeLoadBalancerStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior loadBalancerResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := loadBalancerResourceModel{
+		ID:      prior.ID,
+		Name:    prior.Name,
+		Servers: prior.Servers,
+		Tags:    types.MapNull(types.StringType),
+	}
+	if len(prior.Servers.Elements()) == 0 {
+		upgraded.Servers = types.SetNull(types.StringType)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
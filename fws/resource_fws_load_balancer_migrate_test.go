@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLoadBalancerUpgradeStateV0(t *testing.T) {
+	cases := map[string]struct {
+		raw         string
+		wantServers types.Set
+	}{
+		"with servers": {
+			raw: `{"id": "lb-1", "name": "web", "servers": ["web-1", "web-2"]}`,
+			wantServers: types.SetValueMust(types.StringType, []attr.Value{
+				types.StringValue("web-1"),
+				types.StringValue("web-2"),
+			}),
+		},
+		"empty servers": {
+			raw:         `{"id": "lb-1", "name": "web", "servers": []}`,
+			wantServers: types.SetNull(types.StringType),
+		},
+		"null servers": {
+			raw:         `{"id": "lb-1", "name": "web", "servers": null}`,
+			wantServers: types.SetNull(types.StringType),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			state := upgradeStateV0(t, &loadBalancerResource{}, tc.raw)
+
+			var got loadBalancerResourceModel
+			if diags := state.Get(context.Background(), &got); diags.HasError() {
+				t.Fatalf("error reading upgraded state: %v", diags)
+			}
+			if got.ID.ValueString() != "lb-1" || got.Name.ValueString() != "web" {
+				t.Errorf("got ID %s and name %s, want lb-1 and web", got.ID, got.Name)
+			}
+			if !got.Servers.Equal(tc.wantServers) {
+				t.Errorf("got servers %s, want %s", got.Servers, tc.wantServers)
+			}
+			if !got.Tags.IsNull() {
+				t.Errorf("got tags %s, want null", got.Tags)
+			}
+		})
+	}
+}
@@ -4,156 +4,268 @@
 package fws
 
 import (
+	"context"
 	"fmt"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-provider-fakewebservices/client"
 )
 
-func resourceFWSServer() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceFWSServerCreate,
-		Read:   resourceFWSServerRead,
-		Update: resourceFWSServerUpdate,
-		Delete: resourceFWSServerDelete,
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &serverResource{}
+	_ resource.ResourceWithConfigure   = &serverResource{}
+	_ resource.ResourceWithImportState = &serverResource{}
+)
+
+type serverResource struct {
+	client *client.Client
+}
 
-		Schema: map[string]*schema.Schema{
-			"name": {
+type serverResourceModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+	VPC  types.String `tfsdk:"vpc"`
+	Tags types.Map    `tfsdk:"tags"`
+}
+
+// NewServerResource is a helper function to simplify the provider implementation.
+func NewServerResource() resource.Resource {
+	return &serverResource{}
+}
+
+func (r *serverResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server"
+}
+
+func (r *serverResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of this resource.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
 				Description: "The name of the server.",
-				Type:        schema.TypeString,
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"type": {
+			"type": schema.StringAttribute{
 				Description: "The server type.",
-				Type:        schema.TypeString,
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"vpc": {
+			"vpc": schema.StringAttribute{
 				Description: "The name of the VPC to deploy this server in.",
-				Type:        schema.TypeString,
 				Optional:    true,
 			},
+			"tags": tagsAttribute("server"),
 		},
 	}
 }
 
-func resourceFWSServerCreate(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
-
-	name := d.Get("name").(string)
-	serverType := d.Get("type").(string)
-	vpc := d.Get("vpc").(string)
+func (r *serverResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
 
-	options := ServerCreateOptions{
-		Name: client.String(name),
-		Type: client.String(serverType),
-		VPC:  client.String(vpc),
+	fwsClient, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+		)
+		return
 	}
 
-	req, err := fwsClient.NewRequest("POST", "servers", &options)
+	r.client = fwsClient
+}
+
+func (r *serverResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan serverResourceModel
 
-	if err != nil {
-		return err
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	server := &Server{}
+	tags, diags := expandTags(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	log.Printf("[DEBUG] Creating new server with name: %s", name)
-	err = fwsClient.Do(req, server)
+	options := ServerCreateOptions{
+		Name: client.String(plan.Name.ValueString()),
+		Type: client.String(plan.Type.ValueString()),
+		VPC:  client.String(plan.VPC.ValueString()),
+		Tags: tags,
+	}
+
+	log.Printf("[DEBUG] Creating new server with name: %s", plan.Name.ValueString())
+	server, err := client.Create[*ServerCreateOptions, *Server](ctx, r.client, "servers", &options)
 	if err != nil {
-		return fmt.Errorf("Error creating server: %v", err)
+		resp.Diagnostics.AddError("Error creating server", err.Error())
+		return
 	}
 
-	d.SetId(server.ID)
+	plan.ID = types.StringValue(server.ID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	return resourceFWSServerRead(d, meta)
+	r.read(ctx, &plan, &resp.State, &resp.Diagnostics, true)
 }
 
-func resourceFWSServerRead(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *serverResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state serverResourceModel
 
-	req, err := fwsClient.NewRequest("GET", fmt.Sprintf("servers/%s", d.Id()), nil)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(ctx, &state, &resp.State, &resp.Diagnostics, false)
+}
 
+// read refreshes the given model from the API and stores it in state,
+// removing the resource from state if it no longer exists.
+func (r *serverResource) read(ctx context.Context, m *serverResourceModel, state *tfsdk.State, diags *diag.Diagnostics, afterCreate bool) {
+	id := m.ID.ValueString()
+
+	req, err := r.client.NewRequest("GET", fmt.Sprintf("servers/%s", id), nil)
 	if err != nil {
-		return err
+		diags.AddError("Error reading server", err.Error())
+		return
 	}
 
 	server := &Server{}
 
-	log.Printf("[DEBUG] Reading server: %s", d.Id())
-	err = fwsClient.Do(req, server)
+	log.Printf("[DEBUG] Reading server: %s", id)
+	if afterCreate {
+		err = r.client.DoAfterCreate(ctx, req, server)
+	} else {
+		err = r.client.Do(ctx, req, server)
+	}
 	if err != nil {
-		if err == client.ErrResourceNotFound {
-			log.Printf("[DEBUG] server %s no longer exists", d.Id())
-			d.SetId("")
-			return nil
+		// A new object that never became visible is an error, not an
+		// object that has since been deleted.
+		if err == client.ErrResourceNotFound && !afterCreate {
+			log.Printf("[DEBUG] server %s no longer exists", id)
+			state.RemoveResource(ctx)
+			return
 		}
-		return fmt.Errorf("Error reading configuration of server %s: %v", d.Id(), err)
+		diags.AddError(
+			"Error reading server",
+			fmt.Sprintf("Error reading configuration of server %s: %v", id, err),
+		)
+		return
 	}
 
 	// Update the config.
-	d.Set("name", server.Name)
-	d.Set("type", server.Type)
-	d.Set("vpc", server.VPC)
+	m.Name = types.StringValue(server.Name)
+	m.Type = types.StringValue(server.Type)
+	m.VPC = optionalString(server.VPC)
+
+	tags, d := flattenTags(ctx, m.Tags, server.Tags)
+	diags.Append(d...)
+	m.Tags = tags
 
-	return nil
+	diags.Append(state.Set(ctx, m)...)
 }
 
-func resourceFWSServerUpdate(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *serverResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan serverResourceModel
 
-	name := d.Get("name").(string)
-	serverType := d.Get("type").(string)
-	vpc := d.Get("vpc").(string)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tags, diags := expandTags(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	options := ServerUpdateOptions{
-		Name: client.String(name),
-		Type: client.String(serverType),
-		VPC:  client.String(vpc),
+		Name: client.String(plan.Name.ValueString()),
+		Type: client.String(plan.Type.ValueString()),
+		VPC:  client.String(plan.VPC.ValueString()),
+		Tags: tags,
 	}
 
-	req, err := fwsClient.NewRequest(
+	fwsReq, err := r.client.NewRequest(
 		"PATCH",
-		fmt.Sprintf("servers/%s", d.Id()),
+		fmt.Sprintf("servers/%s", plan.ID.ValueString()),
 		&options,
 	)
-
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error updating server", err.Error())
+		return
 	}
 
 	server := &Server{}
 
-	log.Printf("[DEBUG] Updating server: %s", d.Id())
-	err = fwsClient.Do(req, server)
+	log.Printf("[DEBUG] Updating server: %s", plan.ID.ValueString())
+	err = r.client.Do(ctx, fwsReq, server)
 	if err != nil {
-		return fmt.Errorf("Error updating server: %v", err)
+		resp.Diagnostics.AddError("Error updating server", err.Error())
+		return
 	}
 
-	return resourceFWSServerRead(d, meta)
+	r.read(ctx, &plan, &resp.State, &resp.Diagnostics, false)
 }
 
-func resourceFWSServerDelete(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *serverResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state serverResourceModel
 
-	req, err := fwsClient.NewRequest(
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fwsReq, err := r.client.NewRequest(
 		"DELETE",
-		fmt.Sprintf("servers/%s", d.Id()),
+		fmt.Sprintf("servers/%s", state.ID.ValueString()),
 		nil,
 	)
-
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error destroying server", err.Error())
+		return
 	}
 
-	log.Printf("[DEBUG] Destroying server: %s", d.Id())
-	err = fwsClient.Do(req, nil)
+	log.Printf("[DEBUG] Destroying server: %s", state.ID.ValueString())
+	err = r.client.Do(ctx, fwsReq, nil)
 	if err != nil {
-		return fmt.Errorf("Error destroying server: %v", err)
+		resp.Diagnostics.AddError("Error destroying server", err.Error())
+		return
 	}
+}
 
-	return nil
+func (r *serverResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
 type Server struct {
@@ -162,6 +274,20 @@ type Server struct {
 	Name string `jsonapi:"attr,name,omitempty"`
 	Type string `jsonapi:"attr,server-type,omitempty"`
 	VPC  string `jsonapi:"attr,vpc,omitempty"`
+
+	Tags map[string]interface{} `jsonapi:"attr,tags,omitempty"`
+
+	// ParentVpc is only set when read with ServerIncludeVpc.
+	ParentVpc *Vpc `jsonapi:"relation,parent-vpc,omitempty"`
+}
+
+// ServerIncludeVpc includes a server's VPC when reading it.
+const ServerIncludeVpc = "parent-vpc"
+
+// ServerList represents a page of servers.
+type ServerList struct {
+	*client.Pagination
+	Items []*Server
 }
 
 type ServerCreateOptions struct {
@@ -171,6 +297,8 @@ type ServerCreateOptions struct {
 	Name *string `jsonapi:"attr,name"`
 	Type *string `jsonapi:"attr,server-type"`
 	VPC  *string `jsonapi:"attr,vpc"`
+
+	Tags *map[string]string `jsonapi:"attr,tags"`
 }
 
 type ServerUpdateOptions struct {
@@ -180,4 +308,6 @@ type ServerUpdateOptions struct {
 	Name *string `jsonapi:"attr,name"`
 	Type *string `jsonapi:"attr,server-type"`
 	VPC  *string `jsonapi:"attr,vpc"`
+
+	Tags *map[string]string `jsonapi:"attr,tags"`
 }
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.ResourceWithUpgradeState = &serverResource{}
+
+// serverResourceModelV0 is the state written by the terraform-plugin-sdk
+// implementation of the resource.
+type serverResourceModelV0 struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+	VPC  types.String `tfsdk:"vpc"`
+}
+
+func (r *serverResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"name": schema.StringAttribute{
+						Required: true,
+					},
+					"type": schema.StringAttribute{
+						Required: true,
+					},
+					"vpc": schema.StringAttribute{
+						Optional: true,
+					},
+				},
+			},
+			StateUpgrader: upgradeServerStateV0,
+		},
+	}
+}
+
+// upgradeServerStateV0 carries SDK state over to version 1. The SDK stored
+// an unset vpc as an empty string, which is now represented as null.
+func upgradeServerStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior serverResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := serverResourceModel{
+		ID:   prior.ID,
+		Name: prior.Name,
+		Type: prior.Type,
+		VPC:  optionalString(prior.VPC.ValueString()),
+		Tags: types.MapNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
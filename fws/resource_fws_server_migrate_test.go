@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// upgradeStateV0 feeds raw version 0 state, as JSON, through r's version 0
+// upgrader and returns the upgraded state.
+func upgradeStateV0(t *testing.T, r resource.ResourceWithUpgradeState, rawJSON string) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("no upgrader for version 0")
+	}
+
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx)
+	raw, err := (&tfprotov5.RawState{JSON: []byte(rawJSON)}).Unmarshal(priorType)
+	if err != nil {
+		t.Fatalf("error decoding version 0 state: %v", err)
+	}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Raw: raw, Schema: *upgrader.PriorSchema},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			Schema: schemaResp.Schema,
+		},
+	}
+
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("error upgrading state: %v", resp.Diagnostics)
+	}
+
+	return resp.State
+}
+
+func TestServerUpgradeStateV0(t *testing.T) {
+	cases := map[string]struct {
+		raw  string
+		want serverResourceModel
+	}{
+		"with vpc": {
+			raw: `{"id": "srv-1", "name": "web", "type": "t2.micro", "vpc": "main"}`,
+			want: serverResourceModel{
+				ID:   types.StringValue("srv-1"),
+				Name: types.StringValue("web"),
+				Type: types.StringValue("t2.micro"),
+				VPC:  types.StringValue("main"),
+				Tags: types.MapNull(types.StringType),
+			},
+		},
+		"empty vpc": {
+			raw: `{"id": "srv-1", "name": "web", "type": "t2.micro", "vpc": ""}`,
+			want: serverResourceModel{
+				ID:   types.StringValue("srv-1"),
+				Name: types.StringValue("web"),
+				Type: types.StringValue("t2.micro"),
+				VPC:  types.StringNull(),
+				Tags: types.MapNull(types.StringType),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			state := upgradeStateV0(t, &serverResource{}, tc.raw)
+
+			var got serverResourceModel
+			if diags := state.Get(context.Background(), &got); diags.HasError() {
+				t.Fatalf("error reading upgraded state: %v", diags)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
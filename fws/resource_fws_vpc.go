@@ -4,145 +4,253 @@
 package fws
 
 import (
+	"context"
 	"fmt"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-provider-fakewebservices/client"
 )
 
-func resourceFWSVpc() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceFWSVpcCreate,
-		Read:   resourceFWSVpcRead,
-		Update: resourceFWSVpcUpdate,
-		Delete: resourceFWSVpcDelete,
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &vpcResource{}
+	_ resource.ResourceWithConfigure   = &vpcResource{}
+	_ resource.ResourceWithImportState = &vpcResource{}
+)
+
+type vpcResource struct {
+	client *client.Client
+}
+
+type vpcResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	CidrBlock types.String `tfsdk:"cidr_block"`
+}
+
+// NewVpcResource is a helper function to simplify the provider implementation.
+func NewVpcResource() resource.Resource {
+	return &vpcResource{}
+}
+
+func (r *vpcResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vpc"
+}
 
-		Schema: map[string]*schema.Schema{
-			"name": {
+func (r *vpcResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of this resource.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
 				Description: "The name of the VPC.",
-				Type:        schema.TypeString,
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"cidr_block": {
+			"cidr_block": schema.StringAttribute{
 				Description: "The range of IPv4 addresses for this VPC, in the form of a Classless Inter-Domain Routing (CIDR) block.",
-				Type:        schema.TypeString,
 				Required:    true,
+				Validators: []validator.String{
+					isCIDR(),
+				},
 			},
 		},
 	}
 }
 
-func resourceFWSVpcCreate(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *vpcResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	fwsClient, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = fwsClient
+}
 
-	name := d.Get("name").(string)
-	cb := d.Get("cidr_block").(string)
+func (r *vpcResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan vpcResourceModel
 
-	options := VpcCreateOptions{
-		Name:      client.String(name),
-		CidrBlock: client.String(cb),
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	req, err := fwsClient.NewRequest("POST", "vpcs", &options)
+	options := VpcCreateOptions{
+		Name:      client.String(plan.Name.ValueString()),
+		CidrBlock: client.String(plan.CidrBlock.ValueString()),
+	}
 
+	fwsReq, err := r.client.NewRequest("POST", "vpcs", &options)
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error creating vpc", err.Error())
+		return
 	}
 
 	vpc := &Vpc{}
 
-	log.Printf("[DEBUG] Creating new vpc with name: %s", name)
-	err = fwsClient.Do(req, vpc)
+	log.Printf("[DEBUG] Creating new vpc with name: %s", plan.Name.ValueString())
+	err = r.client.Do(ctx, fwsReq, vpc)
 	if err != nil {
-		return fmt.Errorf("Error creating vpc: %v", err)
+		resp.Diagnostics.AddError("Error creating vpc", err.Error())
+		return
 	}
 
-	d.SetId(vpc.ID)
+	plan.ID = types.StringValue(vpc.ID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	return resourceFWSVpcRead(d, meta)
+	r.read(ctx, &plan, &resp.State, &resp.Diagnostics, true)
 }
 
-func resourceFWSVpcRead(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *vpcResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state vpcResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(ctx, &state, &resp.State, &resp.Diagnostics, false)
+}
 
-	req, err := fwsClient.NewRequest("GET", fmt.Sprintf("vpcs/%s", d.Id()), nil)
+// read refreshes the given model from the API and stores it in state,
+// removing the resource from state if it no longer exists.
+func (r *vpcResource) read(ctx context.Context, m *vpcResourceModel, state *tfsdk.State, diags *diag.Diagnostics, afterCreate bool) {
+	id := m.ID.ValueString()
 
+	req, err := r.client.NewRequest("GET", fmt.Sprintf("vpcs/%s", id), nil)
 	if err != nil {
-		return err
+		diags.AddError("Error reading vpc", err.Error())
+		return
 	}
 
 	vpc := &Vpc{}
 
-	log.Printf("[DEBUG] Reading vpc: %s", d.Id())
-	err = fwsClient.Do(req, vpc)
+	log.Printf("[DEBUG] Reading vpc: %s", id)
+	if afterCreate {
+		err = r.client.DoAfterCreate(ctx, req, vpc)
+	} else {
+		err = r.client.Do(ctx, req, vpc)
+	}
 	if err != nil {
-		if err == client.ErrResourceNotFound {
-			log.Printf("[DEBUG] vpc %s no longer exists", d.Id())
-			d.SetId("")
-			return nil
+		// A new object that never became visible is an error, not an
+		// object that has since been deleted.
+		if err == client.ErrResourceNotFound && !afterCreate {
+			log.Printf("[DEBUG] vpc %s no longer exists", id)
+			state.RemoveResource(ctx)
+			return
 		}
-		return fmt.Errorf("Error reading configuration of vpc %s: %v", d.Id(), err)
+		diags.AddError(
+			"Error reading vpc",
+			fmt.Sprintf("Error reading configuration of vpc %s: %v", id, err),
+		)
+		return
 	}
 
 	// Update the config.
-	d.Set("name", vpc.Name)
+	m.Name = types.StringValue(vpc.Name)
 
-	return nil
+	// The CIDR block is only read on import; otherwise it comes from the
+	// configuration.
+	if m.CidrBlock.IsNull() {
+		m.CidrBlock = types.StringValue(vpc.CidrBlock)
+	}
+
+	diags.Append(state.Set(ctx, m)...)
 }
 
-func resourceFWSVpcUpdate(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *vpcResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan vpcResourceModel
 
-	name := d.Get("name").(string)
-	cb := d.Get("cidr_block").(string)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	options := VpcUpdateOptions{
-		Name:      client.String(name),
-		CidrBlock: client.String(cb),
+		Name:      client.String(plan.Name.ValueString()),
+		CidrBlock: client.String(plan.CidrBlock.ValueString()),
 	}
 
-	req, err := fwsClient.NewRequest(
+	fwsReq, err := r.client.NewRequest(
 		"PATCH",
-		fmt.Sprintf("vpcs/%s", d.Id()),
+		fmt.Sprintf("vpcs/%s", plan.ID.ValueString()),
 		&options,
 	)
-
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error updating vpc", err.Error())
+		return
 	}
 
 	vpc := &Vpc{}
 
-	log.Printf("[DEBUG] Updating vpc: %s", d.Id())
-	err = fwsClient.Do(req, vpc)
+	log.Printf("[DEBUG] Updating vpc: %s", plan.ID.ValueString())
+	err = r.client.Do(ctx, fwsReq, vpc)
 	if err != nil {
-		return fmt.Errorf("Error updating vpc: %v", err)
+		resp.Diagnostics.AddError("Error updating vpc", err.Error())
+		return
 	}
 
-	return resourceFWSVpcRead(d, meta)
+	r.read(ctx, &plan, &resp.State, &resp.Diagnostics, false)
 }
 
-func resourceFWSVpcDelete(d *schema.ResourceData, meta interface{}) error {
-	fwsClient := meta.(*client.Client)
+func (r *vpcResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state vpcResourceModel
 
-	req, err := fwsClient.NewRequest(
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fwsReq, err := r.client.NewRequest(
 		"DELETE",
-		fmt.Sprintf("vpcs/%s", d.Id()),
+		fmt.Sprintf("vpcs/%s", state.ID.ValueString()),
 		nil,
 	)
-
 	if err != nil {
-		return err
+		resp.Diagnostics.AddError("Error destroying vpc", err.Error())
+		return
 	}
 
-	log.Printf("[DEBUG] Destroying vpc: %s", d.Id())
-	err = fwsClient.Do(req, nil)
+	log.Printf("[DEBUG] Destroying vpc: %s", state.ID.ValueString())
+	err = r.client.Do(ctx, fwsReq, nil)
 	if err != nil {
-		return fmt.Errorf("Error destroying vpc: %v", err)
+		resp.Diagnostics.AddError("Error destroying vpc", err.Error())
+		return
 	}
+}
 
-	return nil
+func (r *vpcResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
 type Vpc struct {
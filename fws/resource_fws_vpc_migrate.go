@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ resource.ResourceWithUpgradeState = &vpcResource{}
+
+// vpcResourceModelV0 is the state written by the terraform-plugin-sdk
+// implementation of the resource.
+type vpcResourceModelV0 struct {
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	CidrBlock types.String `tfsdk:"cidr_block"`
+}
+
+func (r *vpcResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"name": schema.StringAttribute{
+						Required: true,
+					},
+					"cidr_block": schema.StringAttribute{
+						Required: true,
+					},
+				},
+			},
+			StateUpgrader: upgradeVpcStateV0,
+		},
+	}
+}
+
+// upgradeVpcStateV0 carries SDK state over to version 1 unchanged.
+func upgradeVpcStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior vpcResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := vpcResourceModel{
+		ID:        prior.ID,
+		Name:      prior.Name,
+		CidrBlock: prior.CidrBlock,
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestVpcUpgradeStateV0(t *testing.T) {
+	state := upgradeStateV0(t, &vpcResource{}, `{"id": "vpc-1", "name": "main", "cidr_block": "10.0.0.0/16"}`)
+
+	var got vpcResourceModel
+	if diags := state.Get(context.Background(), &got); diags.HasError() {
+		t.Fatalf("error reading upgraded state: %v", diags)
+	}
+
+	want := vpcResourceModel{
+		ID:        types.StringValue("vpc-1"),
+		Name:      types.StringValue("main"),
+		CidrBlock: types.StringValue("10.0.0.0/16"),
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
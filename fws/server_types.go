@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+// ServerType describes one of the server types offered by FWS.
+type ServerType struct {
+	Name string
+
+	// VCPU is the number of virtual CPUs.
+	VCPU int
+
+	// Memory is the amount of memory in GiB.
+	Memory float64
+
+	// PricePerHour is the on-demand price in US dollars.
+	PricePerHour float64
+
+	// Available reports whether new servers of this type can be created.
+	Available bool
+}
+
+// serverTypes is the catalog of server types, grouped by family and ordered
+// from smallest to largest within each family.
+var serverTypes = []ServerType{
+	{Name: "t2.nano", VCPU: 1, Memory: 0.5, PricePerHour: 0.0058, Available: true},
+	{Name: "t2.micro", VCPU: 1, Memory: 1, PricePerHour: 0.0116, Available: true},
+	{Name: "t2.small", VCPU: 1, Memory: 2, PricePerHour: 0.023, Available: true},
+	{Name: "t2.medium", VCPU: 2, Memory: 4, PricePerHour: 0.0464, Available: true},
+	{Name: "t2.large", VCPU: 2, Memory: 8, PricePerHour: 0.0928, Available: true},
+	{Name: "t2.xlarge", VCPU: 4, Memory: 16, PricePerHour: 0.1856, Available: true},
+	{Name: "t2.2xlarge", VCPU: 8, Memory: 32, PricePerHour: 0.3712, Available: true},
+	{Name: "m5.large", VCPU: 2, Memory: 8, PricePerHour: 0.096, Available: true},
+	{Name: "m5.xlarge", VCPU: 4, Memory: 16, PricePerHour: 0.192, Available: true},
+	{Name: "m5.2xlarge", VCPU: 8, Memory: 32, PricePerHour: 0.384, Available: true},
+	{Name: "m5.4xlarge", VCPU: 16, Memory: 64, PricePerHour: 0.768, Available: false},
+}
+
+// lookupServerType returns the catalog entry for the named server type.
+func lookupServerType(name string) (ServerType, bool) {
+	for _, t := range serverTypes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return ServerType{}, false
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
+)
+
+// sweepNamePrefix starts the name of every object acceptance tests create,
+// so the sweepers only delete leftovers from tests.
+const sweepNamePrefix = "tf-acc-test"
+
+// TestMain runs the sweepers when -sweep is set, and the tests otherwise:
+//
+//	go test ./fws -v -sweep=all
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	// Objects are swept before the objects they reference: load balancers
+	// before their servers, and servers before their VPCs.
+	resource.AddTestSweepers("fakewebservices_load_balancer", &resource.Sweeper{
+		Name: "fakewebservices_load_balancer",
+		F: sweeper(func(ctx context.Context, c *client.Client) error {
+			return sweep(ctx, c, LoadBalancers(c), "load_balancers", func(lb *LoadBalancer) (string, string) {
+				return lb.ID, lb.Name
+			})
+		}),
+	})
+
+	resource.AddTestSweepers("fakewebservices_server", &resource.Sweeper{
+		Name:         "fakewebservices_server",
+		Dependencies: []string{"fakewebservices_load_balancer"},
+		F: sweeper(func(ctx context.Context, c *client.Client) error {
+			return sweep(ctx, c, Servers(c), "servers", func(server *Server) (string, string) {
+				return server.ID, server.Name
+			})
+		}),
+	})
+
+	resource.AddTestSweepers("fakewebservices_vpc", &resource.Sweeper{
+		Name:         "fakewebservices_vpc",
+		Dependencies: []string{"fakewebservices_server"},
+		F: sweeper(func(ctx context.Context, c *client.Client) error {
+			return sweep(ctx, c, Vpcs(c), "vpcs", func(vpc *Vpc) (string, string) {
+				return vpc.ID, vpc.Name
+			})
+		}),
+	})
+
+	resource.AddTestSweepers("fakewebservices_database", &resource.Sweeper{
+		Name: "fakewebservices_database",
+		F: sweeper(func(ctx context.Context, c *client.Client) error {
+			return sweep(ctx, c, Databases(c), "databases", func(database *Database) (string, string) {
+				return database.ID, database.Name
+			})
+		}),
+	})
+}
+
+// sweeper adapts f to a resource.SweeperFunc, configuring a client the
+// same way the provider does without configuration. FWS has no regions, so
+// the region is ignored.
+func sweeper(f func(context.Context, *client.Client) error) resource.SweeperFunc {
+	return func(_ string) error {
+		c, err := clientConfig{
+			Hostname: DefaultHostname(),
+			Token:    DefaultToken(),
+		}.newClient()
+		if err != nil {
+			return err
+		}
+
+		return f(context.Background(), c)
+	}
+}
+
+// sweep deletes every object in col whose name starts with sweepNamePrefix.
+// idName returns an object's ID and name.
+func sweep[T any](ctx context.Context, c *client.Client, col client.Collection[T], path string, idName func(T) (string, string)) error {
+	// Collect the objects first, as deleting while paging would shift
+	// later objects onto pages already read.
+	var ids []string
+	for obj, err := range col.All(ctx, &client.ListOptions{PageSize: 100}) {
+		if err != nil {
+			return fmt.Errorf("error listing %s: %w", path, err)
+		}
+
+		id, name := idName(obj)
+		if strings.HasPrefix(name, sweepNamePrefix) {
+			ids = append(ids, id)
+		}
+	}
+
+	var errs []error
+	for _, id := range ids {
+		log.Printf("[INFO] Sweeping %s %s", path, id)
+
+		req, err := c.NewRequest("DELETE", fmt.Sprintf("%s/%s", path, id), nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		err = c.Do(ctx, req, nil)
+		if err != nil && err != client.ErrResourceNotFound {
+			errs = append(errs, fmt.Errorf("error sweeping %s %s: %w", path, id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
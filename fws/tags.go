@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// tagsAttribute is the schema of the tags attribute of resources whose
+// objects can be tagged.
+func tagsAttribute(kind string) schema.MapAttribute {
+	return schema.MapAttribute{
+		Description: fmt.Sprintf("Tags to assign to the %s, such as for the cost estimate's breakdown by tag.", kind),
+		ElementType: types.StringType,
+		Optional:    true,
+	}
+}
+
+// expandTags returns the tags to send to the API for m. No tags are sent
+// as an empty map, so that removing them from the configuration clears
+// them.
+func expandTags(ctx context.Context, m types.Map) (*map[string]string, diag.Diagnostics) {
+	tags := map[string]string{}
+	if m.IsNull() || m.IsUnknown() {
+		return &tags, nil
+	}
+	diags := m.ElementsAs(ctx, &tags, false)
+	return &tags, diags
+}
+
+// flattenTags returns the tags read from the API as a map attribute. No
+// tags are null, unless prior is an empty map, so the result matches the
+// configuration either way.
+func flattenTags(ctx context.Context, prior types.Map, tags map[string]interface{}) (types.Map, diag.Diagnostics) {
+	if len(tags) == 0 {
+		if !prior.IsNull() && !prior.IsUnknown() && len(prior.Elements()) == 0 {
+			return prior, nil
+		}
+		return types.MapNull(types.StringType), nil
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, stringTags(tags))
+}
+
+// stringTags converts tags as decoded from the API, where jsonapi can only
+// decode maps into map[string]interface{}, to strings.
+func stringTags(tags map[string]interface{}) map[string]string {
+	if tags == nil {
+		return nil
+	}
+	s := make(map[string]string, len(tags))
+	for k, v := range tags {
+		s[k] = fmt.Sprint(v)
+	}
+	return s
+}
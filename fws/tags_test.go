@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFlattenTags(t *testing.T) {
+	empty := types.MapValueMust(types.StringType, map[string]attr.Value{})
+	tagged := types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("prod")})
+
+	cases := map[string]struct {
+		prior types.Map
+		tags  map[string]interface{}
+		want  types.Map
+	}{
+		"tags":                {prior: types.MapNull(types.StringType), tags: map[string]interface{}{"env": "prod"}, want: tagged},
+		"no tags":             {prior: types.MapNull(types.StringType), want: types.MapNull(types.StringType)},
+		"no tags, configured": {prior: empty, want: empty},
+		"tags removed":        {prior: tagged, want: types.MapNull(types.StringType)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := flattenTags(context.Background(), tc.prior, tc.tags)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpandTags(t *testing.T) {
+	ctx := context.Background()
+
+	// No tags clear any set before.
+	got, diags := expandTags(ctx, types.MapNull(types.StringType))
+	if diags.HasError() || got == nil || len(*got) != 0 {
+		t.Errorf("got %v, %v for null tags, want an empty map", got, diags)
+	}
+
+	got, diags = expandTags(ctx, types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("prod")}))
+	if diags.HasError() || (*got)["env"] != "prod" || len(*got) != 1 {
+		t.Errorf("got %v, %v", got, diags)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fws
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ validator.String = cidrValidator{}
+
+// cidrValidator validates that a string is an IPv4 or IPv6 CIDR block.
+type cidrValidator struct{}
+
+// isCIDR returns a validator which ensures that a string is a valid CIDR
+// block, as accepted by parseCIDR.
+func isCIDR() validator.String {
+	return cidrValidator{}
+}
+
+func (v cidrValidator) Description(_ context.Context) string {
+	return "value must be a valid CIDR block"
+}
+
+func (v cidrValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cidrValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if _, err := parseCIDR(value); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid CIDR Block",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+// parseCIDR parses an IPv4 or IPv6 CIDR block, returning it with any host
+// bits cleared. It is shared by the validator and the provider functions so
+// both agree on what a CIDR block is.
+func parseCIDR(s string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return prefix.Masked(), nil
+}
@@ -8,26 +8,58 @@ import (
 	"flag"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-provider-fakewebservices/client"
 	"github.com/hashicorp/terraform-provider-fakewebservices/fws"
 )
 
+// Version is set at build time by goreleaser.
+var Version = "dev"
+
 func main() {
 	var debugMode bool
 
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	if debugMode {
-		err := plugin.Debug(context.Background(), "registry.terraform.io/hashicorp/fakewebservices",
-			&plugin.ServeOpts{
-				ProviderFunc: fws.Provider,
-			})
-		if err != nil {
-			log.Println(err.Error())
+	ctx := context.Background()
+
+	shutdownTracing, err := client.SetupTracing(ctx, "terraform-provider-fakewebservices", Version)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer func() {
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("[ERROR] Error flushing traces: %s", err)
 		}
-	} else {
-		plugin.Serve(&plugin.ServeOpts{
-			ProviderFunc: fws.Provider})
+	}()
+
+	// The SDK provider is muxed alongside the framework provider while the
+	// migration to terraform-plugin-framework is in progress.
+	providers := []func() tfprotov5.ProviderServer{
+		providerserver.NewProtocol5(fws.New(Version)()),
+		fws.Provider(Version).GRPCProvider,
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	var serveOpts []tf5server.ServeOpt
+	if debugMode {
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
+	}
+
+	err = tf5server.Serve(
+		"registry.terraform.io/hashicorp/fakewebservices",
+		muxServer.ProviderServer,
+		serveOpts...,
+	)
+	if err != nil {
+		log.Fatal(err.Error())
 	}
 }